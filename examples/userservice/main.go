@@ -137,6 +137,7 @@ func handleProviderStates(w http.ResponseWriter, r *http.Request) {
 	var state struct {
 		State  string                 `json:"state"`
 		Params map[string]interface{} `json:"params"`
+		Action string                 `json:"action"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&state); err != nil {
@@ -144,6 +145,13 @@ func handleProviderStates(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Each state is set up again before the interaction that needs it, so
+	// there is nothing to undo after an interaction
+	if state.Action == "teardown" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	log.Printf("Setting up provider state: %s", state.State)
 
 	// Handle different provider states
@@ -2,6 +2,8 @@ package broker
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 
 	"github.com/jt-chihara/yakusoku/internal/broker/ui"
@@ -44,6 +46,17 @@ func (a *API) Handler() http.Handler {
 	// Record verification result
 	mux.HandleFunc("POST /pacts/provider/{provider}/consumer/{consumer}/version/{version}/verification-results", a.handleRecordVerification)
 
+	// Pacts for verification, resolved from consumer version selectors
+	mux.HandleFunc("POST /pacts/provider/{provider}/for-verification", a.handlePactsForVerification)
+
+	// Pacticipant versions: branches, tags, deployments and releases
+	mux.HandleFunc("GET /pacticipants/{pacticipant}", a.handleGetPacticipant)
+	mux.HandleFunc("PATCH /pacticipants/{pacticipant}", a.handleUpdatePacticipant)
+	mux.HandleFunc("PUT /pacticipants/{pacticipant}/branches/{branch}/versions/{version}", a.handleAddBranchVersion)
+	mux.HandleFunc("PUT /pacticipants/{pacticipant}/versions/{version}/tags/{tag}", a.handleAddTag)
+	mux.HandleFunc("POST /pacticipants/{pacticipant}/versions/{version}/deployments/{environment}", a.handleRecordDeployment)
+	mux.HandleFunc("POST /pacticipants/{pacticipant}/versions/{version}/releases/{environment}", a.handleRecordRelease)
+
 	// Matrix / Can I Deploy
 	mux.HandleFunc("GET /matrix", a.handleMatrix)
 
@@ -174,6 +187,96 @@ func (a *API) handleRecordVerification(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "recorded"})
 }
 
+func (a *API) handlePactsForVerification(w http.ResponseWriter, r *http.Request) {
+	provider := r.PathValue("provider")
+
+	var body struct {
+		ConsumerVersionSelectors []ConsumerVersionSelector `json:"consumerVersionSelectors"`
+	}
+	// An empty body selects the latest version of each consumer
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	pacts, err := SelectPactsForVerification(a.storage, provider, body.ConsumerVersionSelectors)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pacts)
+}
+
+func (a *API) handleGetPacticipant(w http.ResponseWriter, r *http.Request) {
+	p, err := a.storage.GetPacticipant(r.PathValue("pacticipant"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func (a *API) handleUpdatePacticipant(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		MainBranch string `json:"mainBranch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	a.updatePacticipant(w, r, http.StatusOK, func(p *Pacticipant) {
+		p.MainBranch = body.MainBranch
+	})
+}
+
+func (a *API) handleAddBranchVersion(w http.ResponseWriter, r *http.Request) {
+	a.updatePacticipant(w, r, http.StatusCreated, func(p *Pacticipant) {
+		p.AddBranch(r.PathValue("version"), r.PathValue("branch"))
+	})
+}
+
+func (a *API) handleAddTag(w http.ResponseWriter, r *http.Request) {
+	a.updatePacticipant(w, r, http.StatusCreated, func(p *Pacticipant) {
+		p.AddTag(r.PathValue("version"), r.PathValue("tag"))
+	})
+}
+
+func (a *API) handleRecordDeployment(w http.ResponseWriter, r *http.Request) {
+	a.updatePacticipant(w, r, http.StatusCreated, func(p *Pacticipant) {
+		p.RecordDeployment(r.PathValue("version"), r.PathValue("environment"))
+	})
+}
+
+func (a *API) handleRecordRelease(w http.ResponseWriter, r *http.Request) {
+	a.updatePacticipant(w, r, http.StatusCreated, func(p *Pacticipant) {
+		p.RecordRelease(r.PathValue("version"), r.PathValue("environment"))
+	})
+}
+
+// updatePacticipant applies update to the pacticipant of the request and
+// responds with the updated pacticipant
+func (a *API) updatePacticipant(w http.ResponseWriter, r *http.Request, status int, update func(p *Pacticipant)) {
+	name := r.PathValue("pacticipant")
+
+	var updated *Pacticipant
+	if err := a.storage.UpdatePacticipant(name, func(p *Pacticipant) {
+		update(p)
+		updated = p.clone()
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(updated)
+}
+
 func (a *API) handleMatrix(w http.ResponseWriter, r *http.Request) {
 	pacticipant := r.URL.Query().Get("pacticipant")
 	version := r.URL.Query().Get("version")
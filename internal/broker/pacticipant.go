@@ -0,0 +1,107 @@
+package broker
+
+import (
+	"errors"
+	"slices"
+)
+
+// ErrPacticipantNotFound indicates that the requested pacticipant was not found
+var ErrPacticipantNotFound = errors.New("pacticipant not found")
+
+// mainBranchCandidates are branch names that become the main branch of a
+// pacticipant that has none when a version is first recorded on them
+var mainBranchCandidates = []string{"main", "master"}
+
+// Pacticipant holds the versions of an application taking part in contracts
+type Pacticipant struct {
+	Name       string `json:"name"`
+	MainBranch string `json:"mainBranch,omitempty"`
+	// Versions are ordered from oldest to newest
+	Versions []PacticipantVersion `json:"versions"`
+}
+
+// PacticipantVersion holds what is known about a version of a pacticipant
+type PacticipantVersion struct {
+	Number   string   `json:"number"`
+	Branches []string `json:"branches,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	// DeployedTo lists the environments the version is currently deployed to
+	DeployedTo []string `json:"deployedTo,omitempty"`
+	// ReleasedTo lists the environments the version is currently released to
+	ReleasedTo []string `json:"releasedTo,omitempty"`
+}
+
+// Version returns the version with the given number, or nil
+func (p *Pacticipant) Version(number string) *PacticipantVersion {
+	for i := range p.Versions {
+		if p.Versions[i].Number == number {
+			return &p.Versions[i]
+		}
+	}
+	return nil
+}
+
+// AddVersion records a version, keeping existing versions in place
+func (p *Pacticipant) AddVersion(number string) *PacticipantVersion {
+	if v := p.Version(number); v != nil {
+		return v
+	}
+	p.Versions = append(p.Versions, PacticipantVersion{Number: number})
+	return &p.Versions[len(p.Versions)-1]
+}
+
+// AddTag tags a version
+func (p *Pacticipant) AddTag(number, tag string) {
+	v := p.AddVersion(number)
+	if !slices.Contains(v.Tags, tag) {
+		v.Tags = append(v.Tags, tag)
+	}
+}
+
+// AddBranch records a version on a branch. The first of "main" or "master"
+// a pacticipant without main branch gets a version on becomes its main
+// branch.
+func (p *Pacticipant) AddBranch(number, branch string) {
+	v := p.AddVersion(number)
+	if !slices.Contains(v.Branches, branch) {
+		v.Branches = append(v.Branches, branch)
+	}
+	if p.MainBranch == "" && slices.Contains(mainBranchCandidates, branch) {
+		p.MainBranch = branch
+	}
+}
+
+// RecordDeployment records a version as deployed to an environment,
+// replacing the version previously deployed there
+func (p *Pacticipant) RecordDeployment(number, environment string) {
+	for i := range p.Versions {
+		p.Versions[i].DeployedTo = slices.DeleteFunc(p.Versions[i].DeployedTo, func(e string) bool {
+			return e == environment
+		})
+	}
+	v := p.AddVersion(number)
+	v.DeployedTo = append(v.DeployedTo, environment)
+}
+
+// RecordRelease records a version as released to an environment. Unlike
+// deployments, several versions can be released to an environment at once.
+func (p *Pacticipant) RecordRelease(number, environment string) {
+	v := p.AddVersion(number)
+	if !slices.Contains(v.ReleasedTo, environment) {
+		v.ReleasedTo = append(v.ReleasedTo, environment)
+	}
+}
+
+// clone returns a deep copy of p
+func (p *Pacticipant) clone() *Pacticipant {
+	c := *p
+	c.Versions = make([]PacticipantVersion, len(p.Versions))
+	for i, v := range p.Versions {
+		v.Branches = slices.Clone(v.Branches)
+		v.Tags = slices.Clone(v.Tags)
+		v.DeployedTo = slices.Clone(v.DeployedTo)
+		v.ReleasedTo = slices.Clone(v.ReleasedTo)
+		c.Versions[i] = v
+	}
+	return &c
+}
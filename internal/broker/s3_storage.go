@@ -57,8 +57,9 @@ func (s *S3Storage) indexS3Key() string {
 
 // index represents the index structure stored in S3
 type index struct {
-	Versions      map[string][]string `json:"versions"`      // pairKey -> sorted versions
-	Verifications map[string]bool     `json:"verifications"` // contractKey -> success
+	Versions      map[string][]string     `json:"versions"`      // pairKey -> sorted versions
+	Verifications map[string]bool         `json:"verifications"` // contractKey -> success
+	Pacticipants  map[string]*Pacticipant `json:"pacticipants,omitempty"`
 }
 
 // loadIndex loads the index from S3
@@ -69,6 +70,7 @@ func (s *S3Storage) loadIndex(ctx context.Context) (*index, error) {
 		return &index{
 			Versions:      make(map[string][]string),
 			Verifications: make(map[string]bool),
+			Pacticipants:  make(map[string]*Pacticipant),
 		}, nil
 	}
 
@@ -83,10 +85,23 @@ func (s *S3Storage) loadIndex(ctx context.Context) (*index, error) {
 	if idx.Verifications == nil {
 		idx.Verifications = make(map[string]bool)
 	}
+	if idx.Pacticipants == nil {
+		idx.Pacticipants = make(map[string]*Pacticipant)
+	}
 
 	return &idx, nil
 }
 
+// pacticipant returns the named pacticipant of the index, creating it if needed
+func (idx *index) pacticipant(name string) *Pacticipant {
+	p, ok := idx.Pacticipants[name]
+	if !ok {
+		p = &Pacticipant{Name: name}
+		idx.Pacticipants[name] = p
+	}
+	return p
+}
+
 // saveIndex saves the index to S3
 func (s *S3Storage) saveIndex(ctx context.Context, idx *index) error {
 	data, err := json.Marshal(idx)
@@ -134,6 +149,7 @@ func (s *S3Storage) SaveContract(c *contract.Contract) error {
 		sort.Strings(versions)
 		idx.Versions[pk] = versions
 	}
+	idx.pacticipant(c.Consumer.Name).AddVersion(version)
 
 	if err := s.saveIndex(ctx, idx); err != nil {
 		return err
@@ -414,3 +430,51 @@ func (s *S3Storage) IsDeployable(pacticipant, version string) (deployable bool,
 
 	return true, "All required verification results are published and successful"
 }
+
+// ListConsumerVersions returns the contract versions of each consumer of a provider
+func (s *S3Storage) ListConsumerVersions(provider string) map[string][]string {
+	ctx := context.Background()
+
+	result := make(map[string][]string)
+	idx, err := s.loadIndex(ctx)
+	if err != nil {
+		return result
+	}
+
+	for pk, versions := range idx.Versions {
+		parts := strings.Split(pk, "|")
+		if len(parts) != 2 || parts[1] != provider || len(versions) == 0 {
+			continue
+		}
+		result[parts[0]] = versions
+	}
+	return result
+}
+
+// GetPacticipant returns a pacticipant and its versions
+func (s *S3Storage) GetPacticipant(name string) (*Pacticipant, error) {
+	ctx := context.Background()
+
+	idx, err := s.loadIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := idx.Pacticipants[name]
+	if !ok {
+		return nil, ErrPacticipantNotFound
+	}
+	return p, nil
+}
+
+// UpdatePacticipant applies update to a pacticipant, creating it if needed
+func (s *S3Storage) UpdatePacticipant(name string, update func(p *Pacticipant)) error {
+	ctx := context.Background()
+
+	idx, err := s.loadIndex(ctx)
+	if err != nil {
+		return err
+	}
+	update(idx.pacticipant(name))
+	return s.saveIndex(ctx, idx)
+}
@@ -0,0 +1,191 @@
+package broker
+
+import (
+	"errors"
+	"slices"
+	"sort"
+)
+
+// ConsumerVersionSelector selects the consumer versions whose contracts a
+// provider should be verified against
+type ConsumerVersionSelector struct {
+	// Consumer restricts the selector to one consumer
+	Consumer string `json:"consumer,omitempty"`
+	// Latest selects the latest version, or the latest with Tag
+	Latest bool `json:"latest,omitempty"`
+	// Tag selects all versions with the tag, or the latest with Latest
+	Tag string `json:"tag,omitempty"`
+	// Branch selects the latest version on the branch
+	Branch string `json:"branch,omitempty"`
+	// MainBranch selects the latest version on each consumer's main branch
+	MainBranch bool `json:"mainBranch,omitempty"`
+	// Deployed and Released select the versions currently deployed or
+	// released to Environment, or to any environment if it is empty.
+	// DeployedOrReleased, or Environment alone, selects both.
+	Deployed           bool   `json:"deployed,omitempty"`
+	Released           bool   `json:"released,omitempty"`
+	DeployedOrReleased bool   `json:"deployedOrReleased,omitempty"`
+	Environment        string `json:"environment,omitempty"`
+}
+
+// SelectedPact identifies a contract version selected for verification
+type SelectedPact struct {
+	Consumer string `json:"consumer"`
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+}
+
+// errEmptySelector reports a selector without any criteria
+var errEmptySelector = errors.New("consumer version selector must set latest, tag, branch, mainBranch, deployed, released, deployedOrReleased or environment")
+
+// validate checks that the selector selects something
+func (sel *ConsumerVersionSelector) validate() error {
+	if !sel.Latest && sel.Tag == "" && sel.Branch == "" && !sel.MainBranch &&
+		!sel.Deployed && !sel.Released && !sel.DeployedOrReleased && sel.Environment == "" {
+		return errEmptySelector
+	}
+	return nil
+}
+
+// onlyLatest reports whether the selector selects only the latest matching version
+func (sel *ConsumerVersionSelector) onlyLatest() bool {
+	if sel.Tag != "" {
+		return sel.Latest
+	}
+	return sel.Branch != "" || sel.MainBranch || (sel.Latest && !sel.selectsEnvironments())
+}
+
+// selectsEnvironments reports whether the selector selects by deployment or release
+func (sel *ConsumerVersionSelector) selectsEnvironments() bool {
+	return sel.Deployed || sel.Released || sel.DeployedOrReleased || sel.Environment != ""
+}
+
+// matches reports whether version of p is selected, ignoring Latest
+func (sel *ConsumerVersionSelector) matches(p *Pacticipant, v *PacticipantVersion) bool {
+	if sel.Tag != "" && !slices.Contains(v.Tags, sel.Tag) {
+		return false
+	}
+	if sel.Branch != "" && !slices.Contains(v.Branches, sel.Branch) {
+		return false
+	}
+	if sel.MainBranch && (p.MainBranch == "" || !slices.Contains(v.Branches, p.MainBranch)) {
+		return false
+	}
+	if sel.selectsEnvironments() {
+		deployed := inEnvironment(v.DeployedTo, sel.Environment)
+		released := inEnvironment(v.ReleasedTo, sel.Environment)
+		switch {
+		case sel.Deployed && !sel.Released && !sel.DeployedOrReleased:
+			return deployed
+		case sel.Released && !sel.Deployed && !sel.DeployedOrReleased:
+			return released
+		default:
+			return deployed || released
+		}
+	}
+	return true
+}
+
+// inEnvironment reports whether environments contains environment, or is
+// not empty if environment is empty
+func inEnvironment(environments []string, environment string) bool {
+	if environment == "" {
+		return len(environments) > 0
+	}
+	return slices.Contains(environments, environment)
+}
+
+// SelectPactsForVerification resolves selectors into the contract versions
+// of provider to verify, without duplicates and ordered by consumer and then
+// from oldest to newest version. Without selectors, the latest version of
+// each consumer is selected.
+//
+// Versions are ordered by when they were first recorded, so "latest" is the
+// most recently published version rather than the last in sort order.
+func SelectPactsForVerification(storage Storage, provider string, selectors []ConsumerVersionSelector) ([]SelectedPact, error) {
+	if len(selectors) == 0 {
+		selectors = []ConsumerVersionSelector{{Latest: true}}
+	}
+	for i := range selectors {
+		if err := selectors[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	consumerVersions := storage.ListConsumerVersions(provider)
+	consumers := make([]string, 0, len(consumerVersions))
+	for consumer := range consumerVersions {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+
+	result := make([]SelectedPact, 0)
+	for _, consumer := range consumers {
+		p, err := storage.GetPacticipant(consumer)
+		if err != nil {
+			p = &Pacticipant{Name: consumer}
+		}
+		versions := orderVersions(p, consumerVersions[consumer])
+
+		selected := make(map[string]bool)
+		for i := range selectors {
+			sel := &selectors[i]
+			if sel.Consumer != "" && sel.Consumer != consumer {
+				continue
+			}
+			var matched []string
+			for _, v := range versions {
+				if sel.matches(p, v) {
+					matched = append(matched, v.Number)
+				}
+			}
+			if sel.onlyLatest() && len(matched) > 1 {
+				matched = matched[len(matched)-1:]
+			}
+			for _, number := range matched {
+				selected[number] = true
+			}
+		}
+
+		for _, v := range versions {
+			if selected[v.Number] {
+				result = append(result, SelectedPact{Consumer: consumer, Provider: provider, Version: v.Number})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// orderVersions returns the metadata of the given contract versions from
+// oldest to newest. Versions unknown to p, e.g. published before versions
+// were recorded, come first in sort order.
+func orderVersions(p *Pacticipant, numbers []string) []*PacticipantVersion {
+	known := make(map[string]bool, len(p.Versions))
+	for i := range p.Versions {
+		known[p.Versions[i].Number] = true
+	}
+
+	result := make([]*PacticipantVersion, 0, len(numbers))
+	unknown := make([]string, 0)
+	for _, number := range numbers {
+		if !known[number] {
+			unknown = append(unknown, number)
+		}
+	}
+	sort.Strings(unknown)
+	for _, number := range unknown {
+		result = append(result, &PacticipantVersion{Number: number})
+	}
+
+	hasContract := make(map[string]bool, len(numbers))
+	for _, number := range numbers {
+		hasContract[number] = true
+	}
+	for i := range p.Versions {
+		if hasContract[p.Versions[i].Number] {
+			result = append(result, &p.Versions[i])
+		}
+	}
+	return result
+}
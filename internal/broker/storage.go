@@ -22,6 +22,9 @@ type Storage interface {
 	RecordVerification(consumer, provider, version string, success bool) error
 	GetVerification(consumer, provider, version string) (success, exists bool)
 	IsDeployable(pacticipant, version string) (deployable bool, reason string)
+	ListConsumerVersions(provider string) map[string][]string
+	GetPacticipant(name string) (*Pacticipant, error)
+	UpdatePacticipant(name string, update func(p *Pacticipant)) error
 }
 
 // contractKey generates a unique key for a contract
@@ -40,6 +43,7 @@ type MemoryStorage struct {
 	contracts     map[string]contract.Contract
 	versions      map[string][]string // pairKey -> sorted versions
 	verifications map[string]bool     // contractKey -> success
+	pacticipants  map[string]*Pacticipant
 }
 
 // NewMemoryStorage creates a new in-memory storage
@@ -48,6 +52,7 @@ func NewMemoryStorage() *MemoryStorage {
 		contracts:     make(map[string]contract.Contract),
 		versions:      make(map[string][]string),
 		verifications: make(map[string]bool),
+		pacticipants:  make(map[string]*Pacticipant),
 	}
 }
 
@@ -77,6 +82,8 @@ func (s *MemoryStorage) SaveContract(c *contract.Contract) error {
 		s.versions[pk] = versions
 	}
 
+	s.pacticipant(c.Consumer.Name).AddVersion(version)
+
 	return nil
 }
 
@@ -267,3 +274,52 @@ func (s *MemoryStorage) IsDeployable(pacticipant, version string) (deployable bo
 
 	return true, "All required verification results are published and successful"
 }
+
+// ListConsumerVersions returns the contract versions of each consumer of a provider
+func (s *MemoryStorage) ListConsumerVersions(provider string) map[string][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]string)
+	for _, c := range s.contracts {
+		if c.Provider.Name != provider {
+			continue
+		}
+		if _, seen := result[c.Consumer.Name]; !seen {
+			result[c.Consumer.Name] = append([]string(nil), s.versions[pairKey(c.Consumer.Name, provider)]...)
+		}
+	}
+	return result
+}
+
+// GetPacticipant returns a copy of a pacticipant and its versions
+func (s *MemoryStorage) GetPacticipant(name string) (*Pacticipant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.pacticipants[name]
+	if !ok {
+		return nil, ErrPacticipantNotFound
+	}
+	return p.clone(), nil
+}
+
+// UpdatePacticipant applies update to a pacticipant, creating it if needed
+func (s *MemoryStorage) UpdatePacticipant(name string, update func(p *Pacticipant)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update(s.pacticipant(name))
+	return nil
+}
+
+// pacticipant returns the named pacticipant, creating it if needed.
+// The caller must hold the write lock.
+func (s *MemoryStorage) pacticipant(name string) *Pacticipant {
+	p, ok := s.pacticipants[name]
+	if !ok {
+		p = &Pacticipant{Name: name}
+		s.pacticipants[name] = p
+	}
+	return p
+}
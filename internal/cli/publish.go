@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -20,6 +21,7 @@ func NewPublishCommand() *cobra.Command {
 	var pactFile string
 	var pactDir string
 	var consumerVersion string
+	var branch string
 	var tags []string
 
 	cmd := &cobra.Command{
@@ -48,7 +50,7 @@ func NewPublishCommand() *cobra.Command {
 				return fmt.Errorf("either --pact-file or --pact-dir is required")
 			}
 
-			return runPublish(cmd, brokerURL, brokerToken, files, consumerVersion, tags)
+			return runPublish(cmd, brokerURL, brokerToken, files, consumerVersion, branch, tags)
 		},
 	}
 
@@ -57,12 +59,13 @@ func NewPublishCommand() *cobra.Command {
 	cmd.Flags().StringVar(&pactFile, "pact-file", "", "Path to a contract file")
 	cmd.Flags().StringVar(&pactDir, "pact-dir", "", "Directory containing contract files")
 	cmd.Flags().StringVar(&consumerVersion, "consumer-version", "", "Version of the consumer (required)")
+	cmd.Flags().StringVar(&branch, "branch", "", "Branch the consumer version was built from")
 	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Tags to apply to the published contracts")
 
 	return cmd
 }
 
-func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []string, version string, tags []string) error {
+func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []string, version, branch string, tags []string) error {
 	parser := contract.NewParser()
 
 	for _, file := range files {
@@ -72,7 +75,7 @@ func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []strin
 		}
 
 		// Build URL
-		url := fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/version/%s",
+		publishURL := fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/version/%s",
 			brokerURL, c.Provider.Name, c.Consumer.Name, version)
 
 		// Read file content
@@ -82,7 +85,7 @@ func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []strin
 		}
 
 		// Create request
-		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+		req, err := http.NewRequest(http.MethodPut, publishURL, bytes.NewReader(data))
 		if err != nil {
 			return fmt.Errorf("failed to create request: %w", err)
 		}
@@ -106,6 +109,15 @@ func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []strin
 
 		fmt.Fprintf(cmd.OutOrStdout(), "Contract %s published successfully\n", filepath.Base(file))
 
+		// Record the branch of the consumer version
+		if branch != "" {
+			branchURL := fmt.Sprintf("%s/pacticipants/%s/branches/%s/versions/%s",
+				brokerURL, c.Consumer.Name, url.PathEscape(branch), version)
+			if err := brokerPut(branchURL, brokerToken); err != nil {
+				return fmt.Errorf("failed to record branch %s: %w", branch, err)
+			}
+		}
+
 		// Apply tags if specified
 		for _, tag := range tags {
 			tagURL := fmt.Sprintf("%s/pacticipants/%s/versions/%s/tags/%s",
@@ -126,3 +138,26 @@ func runPublish(cmd *cobra.Command, brokerURL, brokerToken string, files []strin
 
 	return nil
 }
+
+// brokerPut sends an authenticated PUT request without body to the broker.
+func brokerPut(requestURL, brokerToken string) error {
+	req, err := http.NewRequest(http.MethodPut, requestURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if brokerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+brokerToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s (status %d)", string(body), resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRecordDeploymentCommand creates the record-deployment command
+func NewRecordDeploymentCommand() *cobra.Command {
+	return newRecordCommand("record-deployment", "deployments", "deployed",
+		"Record that a pacticipant version is deployed to an environment",
+		"Record a deployment in the broker. The version replaces the version previously deployed to the environment.")
+}
+
+// NewRecordReleaseCommand creates the record-release command
+func NewRecordReleaseCommand() *cobra.Command {
+	return newRecordCommand("record-release", "releases", "released",
+		"Record that a pacticipant version is released to an environment",
+		"Record a release in the broker. Several versions can be released to an environment at once, e.g. mobile apps.")
+}
+
+// newRecordCommand creates a command that posts a deployment or release to
+// the broker's pacticipant version resource
+func newRecordCommand(use, resource, verb, short, long string) *cobra.Command {
+	var brokerURL string
+	var brokerToken string
+	var pacticipant string
+	var version string
+	var environment string
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if brokerURL == "" {
+				return fmt.Errorf("--broker-url is required")
+			}
+			if pacticipant == "" {
+				return fmt.Errorf("--pacticipant is required")
+			}
+			if version == "" {
+				return fmt.Errorf("--version is required")
+			}
+			if environment == "" {
+				return fmt.Errorf("--environment is required")
+			}
+
+			requestURL := fmt.Sprintf("%s/pacticipants/%s/versions/%s/%s/%s",
+				strings.TrimSuffix(brokerURL, "/"), url.PathEscape(pacticipant),
+				url.PathEscape(version), resource, url.PathEscape(environment))
+			if err := brokerRequest(http.MethodPost, requestURL, brokerToken, nil, func([]byte) error { return nil }); err != nil {
+				return fmt.Errorf("failed to record %s: %w", strings.TrimSuffix(resource, "s"), err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "%s version %s recorded as %s to %s\n", pacticipant, version, verb, environment)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&brokerURL, "broker-url", "", "URL of the Pact broker (required)")
+	cmd.Flags().StringVar(&brokerToken, "broker-token", "", "API token for broker authentication")
+	cmd.Flags().StringVar(&pacticipant, "pacticipant", "", "Name of the pacticipant (required)")
+	cmd.Flags().StringVar(&version, "version", "", "Version of the pacticipant (required)")
+	cmd.Flags().StringVar(&environment, "environment", "", "Environment the version is in (required)")
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jt-chihara/yakusoku/internal/verifier"
+)
+
+// filterRequest is the JSON form of a provider request exchanged with a
+// --request-filter-cmd command. Bodies that are not valid UTF-8 are base64
+// encoded, which Encoding then records as "base64".
+type filterRequest struct {
+	Method   string              `json:"method"`
+	URL      string              `json:"url"`
+	Headers  map[string][]string `json:"headers"`
+	Body     *string             `json:"body,omitempty"`
+	Encoding string              `json:"encoding,omitempty"`
+}
+
+// bodyEncodingBase64 is the filterRequest encoding of base64 encoded bodies.
+const bodyEncodingBase64 = "base64"
+
+// parseCustomHeaders parses --custom-header values of the form "Name: value".
+func parseCustomHeaders(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	headers := make(map[string]string, len(values))
+	for _, value := range values {
+		name, headerValue, ok := strings.Cut(value, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --custom-header %q: expected \"Name: value\"", value)
+		}
+		headers[name] = strings.TrimSpace(headerValue)
+	}
+	return headers, nil
+}
+
+// commandRequestFilter returns a filter that runs command with the shell for
+// every provider request. The command reads the request as JSON on stdin
+// and writes the request to send, in the same form, to stdout. Fields it
+// leaves out keep their values; a body it writes without "encoding" is sent
+// as text.
+func commandRequestFilter(command string) verifier.RequestFilter {
+	return func(req *http.Request) error {
+		var body []byte
+		if req.Body != nil {
+			var err error
+			if body, err = io.ReadAll(req.Body); err != nil {
+				return err
+			}
+			req.Body.Close()
+		}
+		in := filterRequest{Method: req.Method, URL: req.URL.String(), Headers: req.Header}
+		text := string(body)
+		if !utf8.Valid(body) {
+			text = base64.StdEncoding.EncodeToString(body)
+			in.Encoding = bodyEncodingBase64
+		}
+		in.Body = &text
+		input, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		cmd := exec.CommandContext(req.Context(), "sh", "-c", command)
+		cmd.Stdin = bytes.NewReader(input)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("%s: %v: %s", command, err, strings.TrimSpace(stderr.String()))
+		}
+
+		filtered := filterRequest{Method: req.Method, URL: req.URL.String()}
+		if err := json.Unmarshal(output, &filtered); err != nil {
+			return fmt.Errorf("invalid output from %s: %w", command, err)
+		}
+		u, err := url.Parse(filtered.URL)
+		if err != nil {
+			return fmt.Errorf("invalid URL from %s: %w", command, err)
+		}
+
+		req.Method = filtered.Method
+		req.URL = u
+		req.Host = u.Host
+		if filtered.Headers != nil {
+			req.Header = make(http.Header, len(filtered.Headers))
+			for key, values := range filtered.Headers {
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+		}
+		if filtered.Body != nil {
+			switch filtered.Encoding {
+			case "":
+				body = []byte(*filtered.Body)
+			case bodyEncodingBase64:
+				if body, err = base64.StdEncoding.DecodeString(*filtered.Body); err != nil {
+					return fmt.Errorf("invalid base64 body from %s: %w", command, err)
+				}
+			default:
+				return fmt.Errorf("unsupported body encoding %q from %s", filtered.Encoding, command)
+			}
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		return nil
+	}
+}
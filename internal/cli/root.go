@@ -16,7 +16,8 @@ Use yakusoku to:
   - Verify provider APIs against contract files
   - Manage contract files (list, show, diff)
   - Publish contracts to a broker
-  - Check deployment safety with can-i-deploy`,
+  - Check deployment safety with can-i-deploy
+  - Record deployments and releases to environments`,
 	}
 
 	// Add subcommands
@@ -27,6 +28,8 @@ Use yakusoku to:
 	cmd.AddCommand(NewDiffCommand())
 	cmd.AddCommand(NewPublishCommand())
 	cmd.AddCommand(NewCanIDeployCommand())
+	cmd.AddCommand(NewRecordDeploymentCommand())
+	cmd.AddCommand(NewRecordReleaseCommand())
 
 	return cmd
 }
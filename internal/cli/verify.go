@@ -4,6 +4,8 @@ package cli
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,8 +15,25 @@ import (
 
 type verifyOptions struct {
 	providerBaseURL        string
-	pactFile               string
+	pactFiles              []string
+	pactDir                string
+	provider               string
+	brokerURL              string
+	brokerToken            string
+	consumerVersions       []string
+	consumerSelectors      []string
+	publishResults         bool
+	providerAppVersion     string
 	providerStatesSetupURL string
+	providerStatesAsQuery  bool
+	parallel               int
+	requestTimeout         time.Duration
+	retries                int
+	retryBackoff           time.Duration
+	waitURL                string
+	waitTimeout            time.Duration
+	customHeaders          []string
+	requestFilterCmd       string
 	verbose                bool
 }
 
@@ -24,8 +43,8 @@ func NewVerifyCommand() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:          "verify",
-		Short:        "Verify a provider against a contract file",
-		Long:         "Verify that a provider API satisfies the expectations defined in a Pact contract file.",
+		Short:        "Verify a provider against contract files",
+		Long:         "Verify that a provider API satisfies the expectations defined in one or more Pact contract files.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return runVerify(cmd, opts)
@@ -33,12 +52,28 @@ func NewVerifyCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&opts.providerBaseURL, "provider-base-url", "", "Base URL of the provider API (required)")
-	cmd.Flags().StringVar(&opts.pactFile, "pact-file", "", "Path to the Pact contract file (required)")
+	cmd.Flags().StringArrayVar(&opts.pactFiles, "pact-file", nil, "Path or glob pattern of Pact contract files (repeatable)")
+	cmd.Flags().StringVar(&opts.pactDir, "pact-dir", "", "Directory containing Pact contract files")
+	cmd.Flags().StringVar(&opts.provider, "provider", "", "Only verify contracts for this provider name (required with --broker-url)")
+	cmd.Flags().StringVar(&opts.brokerURL, "broker-url", "", "URL of the Pact broker to fetch the provider's contracts from")
+	cmd.Flags().StringVar(&opts.brokerToken, "broker-token", "", "API token for broker authentication")
+	cmd.Flags().StringArrayVar(&opts.consumerVersions, "consumer-version", nil, "Consumer version to fetch from the broker instead of the latest, as \"Consumer=version\" (repeatable)")
 	cmd.Flags().StringVar(&opts.providerStatesSetupURL, "provider-states-setup-url", "", "URL for provider states setup")
+	cmd.Flags().BoolVar(&opts.providerStatesAsQuery, "provider-states-as-query", false, "Send provider state changes as query parameters instead of a JSON body")
+	cmd.Flags().IntVar(&opts.parallel, "parallel", 1, "Number of interactions to verify concurrently")
+	cmd.Flags().DurationVar(&opts.requestTimeout, "request-timeout", 30*time.Second, "Timeout for each request to the provider (0 for none)")
+	cmd.Flags().IntVar(&opts.retries, "retries", 0, "Number of times to retry a request when the connection to the provider fails")
+	cmd.Flags().DurationVar(&opts.retryBackoff, "retry-backoff", 500*time.Millisecond, "Delay before the first retry, doubled for each further retry")
+	cmd.Flags().StringVar(&opts.waitURL, "wait-for-provider-url", "", "Health check URL to poll until the provider is ready before verifying")
+	cmd.Flags().DurationVar(&opts.waitTimeout, "wait-timeout", 30*time.Second, "Maximum time to wait for the provider to become ready")
+	cmd.Flags().StringArrayVar(&opts.customHeaders, "custom-header", nil, "Header to set on every provider request, as \"Name: value\" (repeatable)")
+	cmd.Flags().StringVar(&opts.requestFilterCmd, "request-filter-cmd", "", "Shell command that rewrites each provider request, read as JSON on stdin and written to stdout")
+	cmd.Flags().StringArrayVar(&opts.consumerSelectors, "consumer-version-selector", nil, "JSON consumer version selector resolved by the broker, e.g. '{\"deployedOrReleased\":true,\"environment\":\"production\"}' (repeatable)")
+	cmd.Flags().BoolVar(&opts.publishResults, "publish-verification-results", false, "Publish verification results of contracts fetched from the broker")
+	cmd.Flags().StringVar(&opts.providerAppVersion, "provider-app-version", "", "Version of the provider, required to publish verification results")
 	cmd.Flags().BoolVar(&opts.verbose, "verbose", false, "Show detailed output")
 
 	_ = cmd.MarkFlagRequired("provider-base-url")
-	_ = cmd.MarkFlagRequired("pact-file")
 
 	return cmd
 }
@@ -48,46 +83,196 @@ func runVerify(cmd *cobra.Command, opts *verifyOptions) error {
 	if opts.providerBaseURL == "" {
 		return fmt.Errorf("--provider-base-url is required")
 	}
-	if opts.pactFile == "" {
-		return fmt.Errorf("--pact-file is required")
+	if len(opts.pactFiles) == 0 && opts.pactDir == "" && opts.brokerURL == "" {
+		return fmt.Errorf("one of --pact-file, --pact-dir or --broker-url is required")
 	}
-
-	// Check if file exists
-	if _, err := os.Stat(opts.pactFile); os.IsNotExist(err) {
-		return fmt.Errorf("pact file not found: %s", opts.pactFile)
+	if opts.brokerURL != "" && opts.provider == "" {
+		return fmt.Errorf("--provider is required with --broker-url")
+	}
+	if len(opts.consumerVersions) > 0 && len(opts.consumerSelectors) > 0 {
+		return fmt.Errorf("--consumer-version and --consumer-version-selector cannot be used together")
+	}
+	if opts.publishResults {
+		if opts.brokerURL == "" {
+			return fmt.Errorf("--broker-url is required with --publish-verification-results")
+		}
+		if opts.providerAppVersion == "" {
+			return fmt.Errorf("--provider-app-version is required with --publish-verification-results")
+		}
+	}
+	if opts.parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	if opts.retries < 0 {
+		return fmt.Errorf("--retries must not be negative")
+	}
+	customHeaders, err := parseCustomHeaders(opts.customHeaders)
+	if err != nil {
+		return err
+	}
+	var requestFilter verifier.RequestFilter
+	if opts.requestFilterCmd != "" {
+		requestFilter = commandRequestFilter(opts.requestFilterCmd)
 	}
 
-	// Parse contract
-	parser := contract.NewParser()
-	c, err := parser.ParseFile(opts.pactFile)
+	pacts, err := loadContracts(opts)
 	if err != nil {
-		return fmt.Errorf("failed to parse pact file: %w", err)
+		return err
 	}
 
-	// Verify contract
+	// Verify contracts
 	v := verifier.New(verifier.Config{
 		ProviderBaseURL:        opts.providerBaseURL,
 		ProviderStatesSetupURL: opts.providerStatesSetupURL,
+		ProviderStatesAsQuery:  opts.providerStatesAsQuery,
+		Parallel:               opts.parallel,
+		RequestTimeout:         opts.requestTimeout,
+		Retries:                opts.retries,
+		RetryBackoff:           opts.retryBackoff,
+		WaitURL:                opts.waitURL,
+		WaitTimeout:            opts.waitTimeout,
+		CustomHeaders:          customHeaders,
+		RequestFilter:          requestFilter,
 	})
 
-	result, err := v.Verify(c)
-	if err != nil {
-		return fmt.Errorf("verification failed: %w", err)
-	}
-
-	// Report results
 	reporter := verifier.NewReporter(cmd.OutOrStdout())
 	reporter.SetVerbose(opts.verbose)
-	reporter.Report(result)
+
+	results := make([]*verifier.VerificationResult, 0, len(pacts))
+	failed := 0
+	var publishErr error
+	for i, pact := range pacts {
+		c := pact.contract
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Verifying a pact between %s and %s\n", c.Consumer.Name, c.Provider.Name)
+
+		result, err := v.Verify(c)
+		if err != nil {
+			return fmt.Errorf("verification failed: %w", err)
+		}
+		reporter.Report(result)
+		results = append(results, result)
+		failed += countFailed(result.Interactions)
+
+		// Only contracts fetched from the broker have a consumer version
+		// to publish results for
+		if opts.publishResults && pact.consumerVersion != "" {
+			err := publishVerificationResults(opts.brokerURL, opts.brokerToken, pact, opts.providerAppVersion, result)
+			if err != nil {
+				publishErr = fmt.Errorf("%s version %s: %w", c.Consumer.Name, pact.consumerVersion, err)
+				fmt.Fprintf(cmd.OutOrStdout(), "Failed to publish verification results: %v\n", err)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Verification results published for %s version %s\n", c.Consumer.Name, pact.consumerVersion)
+			}
+		}
+	}
+	if len(results) > 1 {
+		reporter.ReportTotal(results)
+	}
 
 	// Return error if verification failed (for exit code)
-	if !result.Success {
-		return fmt.Errorf("verification failed: %d interactions failed", countFailed(result.Interactions))
+	if failed > 0 {
+		return fmt.Errorf("verification failed: %d interactions failed", failed)
+	}
+	if publishErr != nil {
+		return publishErr
 	}
 
 	return nil
 }
 
+// loadContracts parses the contract files selected by --pact-file and
+// --pact-dir, keeping those for --provider if it is set, and fetches the
+// provider's contracts from --broker-url.
+func loadContracts(opts *verifyOptions) ([]pactToVerify, error) {
+	var files []string
+	if len(opts.pactFiles) > 0 || opts.pactDir != "" {
+		var err error
+		if files, err = resolvePactFiles(opts.pactFiles, opts.pactDir); err != nil {
+			return nil, err
+		}
+	}
+
+	parser := contract.NewParser()
+	var pacts []pactToVerify
+	for _, file := range files {
+		c, err := parser.ParseFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pact file %s: %w", file, err)
+		}
+		if opts.provider != "" && c.Provider.Name != opts.provider {
+			continue
+		}
+		pacts = append(pacts, pactToVerify{contract: c})
+	}
+
+	if opts.brokerURL != "" {
+		versions, err := parseConsumerVersions(opts.consumerVersions)
+		if err != nil {
+			return nil, err
+		}
+		selectors, err := parseConsumerVersionSelectors(opts.consumerSelectors)
+		if err != nil {
+			return nil, err
+		}
+		fetched, err := fetchBrokerContracts(opts.brokerURL, opts.brokerToken, opts.provider, versions, selectors)
+		if err != nil {
+			return nil, err
+		}
+		pacts = append(pacts, fetched...)
+	}
+
+	if len(pacts) == 0 {
+		return nil, fmt.Errorf("no contracts found for provider %q", opts.provider)
+	}
+	return pacts, nil
+}
+
+// resolvePactFiles expands the --pact-file values, which may be glob
+// patterns, and the JSON files in --pact-dir into a list of files without
+// duplicates.
+func resolvePactFiles(patterns []string, dir string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(matches []string) {
+		for _, file := range matches {
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pact file pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("pact file not found: %s", pattern)
+		}
+		add(matches)
+	}
+
+	if dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("pact directory not found: %s", dir)
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find contracts: %w", err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no pact files found in %s", dir)
+		}
+		add(matches)
+	}
+
+	return files, nil
+}
+
 func countFailed(interactions []verifier.InteractionResult) int {
 	count := 0
 	for i := range interactions {
@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/broker"
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+	"github.com/jt-chihara/yakusoku/internal/verifier"
+)
+
+// brokerPact identifies a contract published to the broker.
+type brokerPact struct {
+	Consumer string `json:"consumer"`
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+}
+
+// pactToVerify is a contract to verify together with the consumer version
+// it was published with, which is empty for local contract files.
+type pactToVerify struct {
+	contract        *contract.Contract
+	consumerVersion string
+}
+
+// verificationResults is the body posted to the broker's
+// verification-results endpoint.
+type verificationResults struct {
+	Success         bool                    `json:"success"`
+	ProviderVersion string                  `json:"providerVersion"`
+	TestResults     []interactionTestResult `json:"testResults"`
+}
+
+// interactionTestResult is the result of verifying one interaction.
+type interactionTestResult struct {
+	InteractionDescription string             `json:"interactionDescription"`
+	Success                bool               `json:"success"`
+	Mismatches             []matcher.Mismatch `json:"mismatches,omitempty"`
+	Error                  string             `json:"error,omitempty"`
+}
+
+// parseConsumerVersions parses --consumer-version values of the form
+// "Consumer=version".
+func parseConsumerVersions(values []string) (map[string]string, error) {
+	versions := make(map[string]string, len(values))
+	for _, value := range values {
+		consumer, version, ok := strings.Cut(value, "=")
+		if !ok || consumer == "" || version == "" {
+			return nil, fmt.Errorf("invalid --consumer-version %q: expected \"Consumer=version\"", value)
+		}
+		versions[consumer] = version
+	}
+	return versions, nil
+}
+
+// parseConsumerVersionSelectors parses --consumer-version-selector values,
+// each a JSON selector such as {"mainBranch": true}.
+func parseConsumerVersionSelectors(values []string) ([]broker.ConsumerVersionSelector, error) {
+	selectors := make([]broker.ConsumerVersionSelector, 0, len(values))
+	for _, value := range values {
+		var selector broker.ConsumerVersionSelector
+		decoder := json.NewDecoder(strings.NewReader(value))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&selector); err != nil {
+			return nil, fmt.Errorf("invalid --consumer-version-selector %q: %w", value, err)
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// fetchBrokerContracts fetches the contracts of provider from the broker.
+// With selectors, the broker resolves the consumer versions to verify.
+// Otherwise the latest contract of every consumer is fetched, and consumers
+// in versions are fetched at the given version instead, even if the broker
+// does not list them.
+func fetchBrokerContracts(brokerURL, brokerToken, provider string, versions map[string]string, selectors []broker.ConsumerVersionSelector) ([]pactToVerify, error) {
+	brokerURL = strings.TrimSuffix(brokerURL, "/")
+
+	var pacts []brokerPact
+	var err error
+	if len(selectors) > 0 {
+		pacts, err = selectBrokerPacts(brokerURL, brokerToken, provider, selectors)
+	} else {
+		pacts, err = listBrokerPacts(brokerURL, brokerToken, provider, versions)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	parser := contract.NewParser()
+	fetched := make([]pactToVerify, 0, len(pacts))
+	for _, pact := range pacts {
+		pactURL := fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/version/%s",
+			brokerURL, url.PathEscape(provider), url.PathEscape(pact.Consumer), url.PathEscape(pact.Version))
+
+		var c *contract.Contract
+		if err := brokerRequest(http.MethodGet, pactURL, brokerToken, nil, func(body []byte) error {
+			var err error
+			c, err = parser.ParseBytes(body)
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("failed to fetch contract %s version %s: %w", pact.Consumer, pact.Version, err)
+		}
+		fetched = append(fetched, pactToVerify{contract: c, consumerVersion: pact.Version})
+	}
+
+	return fetched, nil
+}
+
+// listBrokerPacts lists the latest contract version of every consumer of
+// provider, replacing those of the consumers in versions.
+func listBrokerPacts(brokerURL, brokerToken, provider string, versions map[string]string) ([]brokerPact, error) {
+	var listed []brokerPact
+	listURL := fmt.Sprintf("%s/pacts/provider/%s", brokerURL, url.PathEscape(provider))
+	if err := brokerRequest(http.MethodGet, listURL, brokerToken, nil, func(body []byte) error {
+		return json.Unmarshal(body, &listed)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list contracts for %s: %w", provider, err)
+	}
+
+	selected := make(map[string]string, len(listed)+len(versions))
+	for _, pact := range listed {
+		selected[pact.Consumer] = pact.Version
+	}
+	for consumer, version := range versions {
+		selected[consumer] = version
+	}
+	consumers := make([]string, 0, len(selected))
+	for consumer := range selected {
+		consumers = append(consumers, consumer)
+	}
+	sort.Strings(consumers)
+
+	pacts := make([]brokerPact, len(consumers))
+	for i, consumer := range consumers {
+		pacts[i] = brokerPact{Consumer: consumer, Provider: provider, Version: selected[consumer]}
+	}
+	return pacts, nil
+}
+
+// selectBrokerPacts asks the broker for the contract versions of provider
+// matching selectors.
+func selectBrokerPacts(brokerURL, brokerToken, provider string, selectors []broker.ConsumerVersionSelector) ([]brokerPact, error) {
+	data, err := json.Marshal(map[string]interface{}{"consumerVersionSelectors": selectors})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode consumer version selectors: %w", err)
+	}
+
+	var pacts []brokerPact
+	selectURL := fmt.Sprintf("%s/pacts/provider/%s/for-verification", brokerURL, url.PathEscape(provider))
+	if err := brokerRequest(http.MethodPost, selectURL, brokerToken, data, func(body []byte) error {
+		return json.Unmarshal(body, &pacts)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to select contracts for %s: %w", provider, err)
+	}
+	return pacts, nil
+}
+
+// publishVerificationResults posts the result of verifying pact against
+// providerVersion to the broker.
+func publishVerificationResults(brokerURL, brokerToken string, pact pactToVerify, providerVersion string, result *verifier.VerificationResult) error {
+	results := verificationResults{
+		Success:         result.Success,
+		ProviderVersion: providerVersion,
+		TestResults:     make([]interactionTestResult, len(result.Interactions)),
+	}
+	for i := range result.Interactions {
+		ir := &result.Interactions[i]
+		results.TestResults[i] = interactionTestResult{
+			InteractionDescription: ir.Description,
+			Success:                ir.Success,
+			Mismatches:             ir.Mismatches,
+			Error:                  ir.Error,
+		}
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode verification results: %w", err)
+	}
+
+	c := pact.contract
+	requestURL := fmt.Sprintf("%s/pacts/provider/%s/consumer/%s/version/%s/verification-results",
+		strings.TrimSuffix(brokerURL, "/"), url.PathEscape(c.Provider.Name),
+		url.PathEscape(c.Consumer.Name), url.PathEscape(pact.consumerVersion))
+	req, err := http.NewRequest(http.MethodPost, requestURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if brokerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+brokerToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish verification results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to publish verification results: %s (status %d)", strings.TrimSpace(string(body)), resp.StatusCode)
+	}
+	return nil
+}
+
+// brokerRequest sends an authenticated request with an optional JSON body
+// to the broker and passes the body of a successful (2xx) response to
+// decode.
+func brokerRequest(method, requestURL, brokerToken string, data []byte, decode func(body []byte) error) error {
+	var reqBody io.Reader = http.NoBody
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, requestURL, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if brokerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+brokerToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query broker: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s (status %d)", strings.TrimSpace(string(body)), resp.StatusCode)
+	}
+	return decode(body)
+}
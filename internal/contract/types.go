@@ -1,6 +1,12 @@
 // Package contract provides types and utilities for Pact v3 contract files.
 package contract
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
 // Contract represents a Pact v3 contract file.
 type Contract struct {
 	Consumer     Pacticipant   `json:"consumer"`
@@ -34,13 +40,60 @@ type ProviderState struct {
 type Request struct {
 	Method        string                 `json:"method"`
 	Path          string                 `json:"path"`
-	Query         map[string][]string    `json:"query,omitempty"`
+	Query         Query                  `json:"query,omitempty"`
 	Headers       map[string]interface{} `json:"headers,omitempty"`
 	Body          interface{}            `json:"body,omitempty"`
 	MatchingRules MatchingRules          `json:"matchingRules,omitempty"`
 	Generators    Generators             `json:"generators,omitempty"`
 }
 
+// Query holds request query parameters keyed by name. Repeated keys keep
+// their values in order.
+type Query map[string][]string
+
+// UnmarshalJSON accepts the Pact v3 map form, whose values may be a single
+// string or a list, and the raw query string used by Pact v2 contracts.
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		values, err := url.ParseQuery(raw)
+		if err != nil {
+			return fmt.Errorf("invalid query string %q: %w", raw, err)
+		}
+		*q = Query(values)
+		return nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if fields == nil {
+		*q = nil
+		return nil
+	}
+	result := make(Query, len(fields))
+	for key, field := range fields {
+		var single string
+		if err := json.Unmarshal(field, &single); err == nil {
+			result[key] = []string{single}
+			continue
+		}
+		var values []string
+		if err := json.Unmarshal(field, &values); err != nil {
+			return fmt.Errorf("invalid value for query parameter %q: %w", key, err)
+		}
+		result[key] = values
+	}
+	*q = result
+	return nil
+}
+
+// Encode returns the query in URL-encoded form, sorted by key.
+func (q Query) Encode() string {
+	return url.Values(q).Encode()
+}
+
 // Response represents an expected HTTP response.
 type Response struct {
 	Status        int                    `json:"status"`
@@ -56,6 +109,7 @@ type MatchingRules struct {
 	Headers map[string]MatcherSet `json:"headers,omitempty"`
 	Path    MatcherSet            `json:"path,omitempty"`
 	Query   map[string]MatcherSet `json:"query,omitempty"`
+	Status  *MatcherSet           `json:"status,omitempty"`
 }
 
 // MatcherSet is a set of matchers with an optional combine strategy.
@@ -66,11 +120,23 @@ type MatcherSet struct {
 
 // Matcher represents a single matching rule.
 type Matcher struct {
-	Match string      `json:"match"`
-	Regex string      `json:"regex,omitempty"`
-	Value interface{} `json:"value,omitempty"`
-	Min   *int        `json:"min,omitempty"`
-	Max   *int        `json:"max,omitempty"`
+	Match    string      `json:"match"`
+	Regex    string      `json:"regex,omitempty"`
+	Format   string      `json:"format,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+	Min      *int        `json:"min,omitempty"`
+	Max      *int        `json:"max,omitempty"`
+	Status   interface{} `json:"status,omitempty"`
+	Rules    []Matcher   `json:"rules,omitempty"`
+	Variants []Variant   `json:"variants,omitempty"`
+}
+
+// Variant is one expected element of an arrayContains matcher (Pact v4).
+// Its rules are rooted at the element.
+type Variant struct {
+	Index      int                   `json:"index"`
+	Rules      map[string]MatcherSet `json:"rules,omitempty"`
+	Generators map[string]Generator  `json:"generators,omitempty"`
 }
 
 // Generators defines value generators (Pact v3).
@@ -83,12 +149,14 @@ type Generators struct {
 
 // Generator represents a value generator.
 type Generator struct {
-	Type   string                 `json:"type"`
-	Format string                 `json:"format,omitempty"`
-	Min    *int                   `json:"min,omitempty"`
-	Max    *int                   `json:"max,omitempty"`
-	Digits *int                   `json:"digits,omitempty"`
-	Values map[string]interface{} `json:"values,omitempty"`
+	Type       string                 `json:"type"`
+	Format     string                 `json:"format,omitempty"`
+	Expression string                 `json:"expression,omitempty"`
+	DataType   string                 `json:"dataType,omitempty"`
+	Min        *int                   `json:"min,omitempty"`
+	Max        *int                   `json:"max,omitempty"`
+	Digits     *int                   `json:"digits,omitempty"`
+	Values     map[string]interface{} `json:"values,omitempty"`
 }
 
 // Metadata contains contract file metadata.
@@ -0,0 +1,72 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// ArrayContainsMatcher matches arrays that contain an element matching each
+// variant of the rule, in any order. Each variant is compared with its own
+// rules, rooted at the element.
+type ArrayContainsMatcher struct {
+	comparator *Comparator
+}
+
+// NewArrayContainsMatcher creates a new ArrayContainsMatcher that compares
+// variants with c.
+func NewArrayContainsMatcher(c *Comparator) *ArrayContainsMatcher {
+	return &ArrayContainsMatcher{comparator: c}
+}
+
+// Name returns "arrayContains".
+func (m *ArrayContainsMatcher) Name() string {
+	return "arrayContains"
+}
+
+// Match checks that actual contains every element of expected.
+func (m *ArrayContainsMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	exp, _ := expected.([]interface{})
+	variants := make([]contract.Variant, len(exp))
+	for i := range exp {
+		variants[i] = contract.Variant{Index: i}
+	}
+	return m.MatchRule(contract.Matcher{Match: "arrayContains", Variants: variants}, expected, actual)
+}
+
+// MatchRule checks that every variant of rule matches some element of actual.
+func (m *ArrayContainsMatcher) MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	exp, ok := expected.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("arrayContains matcher requires an expected array, got %s", jsonType(expected))
+	}
+	act, ok := actual.([]interface{})
+	if !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected array, got %s", jsonType(actual)),
+		}, nil
+	}
+
+	var diffs []string
+	for _, variant := range rule.Variants {
+		if variant.Index < 0 || variant.Index >= len(exp) {
+			return nil, fmt.Errorf("arrayContains variant index %d out of range", variant.Index)
+		}
+		found := false
+		for _, elem := range act {
+			result, err := m.comparator.CompareBody(exp[variant.Index], elem, variant.Rules)
+			if err != nil {
+				return nil, err
+			}
+			if result.Matched {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diffs = append(diffs, fmt.Sprintf("no element matches variant %d (%v)", variant.Index, exp[variant.Index]))
+		}
+	}
+	return messageResult(diffs), nil
+}
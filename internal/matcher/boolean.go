@@ -0,0 +1,27 @@
+package matcher
+
+import "fmt"
+
+// BooleanMatcher matches JSON booleans.
+type BooleanMatcher struct{}
+
+// NewBooleanMatcher creates a new BooleanMatcher.
+func NewBooleanMatcher() *BooleanMatcher {
+	return &BooleanMatcher{}
+}
+
+// Name returns "boolean".
+func (m *BooleanMatcher) Name() string {
+	return "boolean"
+}
+
+// Match checks that actual is a boolean.
+func (m *BooleanMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	if _, ok := actual.(bool); !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a boolean, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
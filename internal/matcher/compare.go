@@ -1,11 +1,17 @@
 package matcher
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
 )
 
+// equalitySet is used for values that have no matching rule.
+var equalitySet = contract.MatcherSet{Matchers: []contract.Matcher{{Match: "equality"}}}
+
 // Comparator orchestrates matching using the appropriate matchers.
 type Comparator struct {
 	matchers map[string]Matcher
@@ -18,6 +24,24 @@ func NewComparator() *Comparator {
 	}
 	// Register default matchers
 	c.RegisterMatcher(NewEqualityMatcher())
+	c.RegisterMatcher(NewTypeMatcher())
+	c.RegisterMatcher(NewRegexMatcher())
+	c.RegisterMatcher(NewNumberMatcher())
+	c.RegisterMatcher(NewIntegerMatcher())
+	c.RegisterMatcher(NewDecimalMatcher())
+	c.RegisterMatcher(NewDateMatcher())
+	c.RegisterMatcher(NewTimeMatcher())
+	c.RegisterMatcher(NewTimestampMatcher())
+	c.RegisterMatcher(NewIncludeMatcher())
+	c.RegisterMatcher(NewNullMatcher())
+	c.RegisterMatcher(NewBooleanMatcher())
+	c.RegisterMatcher(NewSemverMatcher())
+	c.RegisterMatcher(NewNotEmptyMatcher())
+	c.RegisterMatcher(NewStatusCodeMatcher())
+	c.RegisterMatcher(NewContentTypeMatcher())
+	c.RegisterMatcher(NewArrayContainsMatcher(c))
+	c.RegisterMatcher(NewEachKeyMatcher(c))
+	c.RegisterMatcher(NewEachValueMatcher(c))
 	return c
 }
 
@@ -34,54 +58,519 @@ func (c *Comparator) GetMatcher(name string) (Matcher, bool) {
 
 // Compare compares expected and actual values using the specified matching rules.
 func (c *Comparator) Compare(expected, actual interface{}, rules contract.MatchingRules) (*MatchResult, error) {
-	// If no rules, use equality matching
-	if len(rules.Body) == 0 && len(rules.Headers) == 0 && len(rules.Query) == 0 {
-		return c.matchers["equality"].Match(expected, actual)
-	}
-
-	// For now, just do equality matching
-	// TODO: implement path-based matching with rules
-	return c.matchers["equality"].Match(expected, actual)
+	return c.CompareBody(expected, actual, rules.Body)
 }
 
 // CompareBody compares body values using the specified body matching rules.
+// Every node of the expected body is checked with the matcher set registered
+// for the most specific rule path that applies to it (e.g. "$.items[*].id"),
+// falling back to equality where no rule applies. A rule also applies below
+// the path it names, but its min/max only bound the array it names. Fields
+// that only exist in the actual body are ignored.
 func (c *Comparator) CompareBody(expected, actual interface{}, rules map[string]contract.MatcherSet) (*MatchResult, error) {
-	if len(rules) == 0 {
-		return c.matchers["equality"].Match(expected, actual)
+	parsed, err := newBodyRules(rules)
+	if err != nil {
+		return nil, err
 	}
 
-	// For now, use equality matching
-	// TODO: implement path-based matching
-	return c.matchers["equality"].Match(expected, actual)
+	mismatches, err := c.compareNode([]string{rootToken}, expected, actual, parsed)
+	if err != nil {
+		return nil, err
+	}
+	return diffResult(mismatches), nil
 }
 
 // CompareHeaders compares headers using the specified header matching rules.
+// Header names are matched ignoring case. Headers without a rule are
+// compared value by value (see headerValuesEqual); extra actual headers are
+// ignored.
 func (c *Comparator) CompareHeaders(expected, actual map[string]interface{}, rules map[string]contract.MatcherSet) (*MatchResult, error) {
 	if expected == nil && actual == nil {
 		return &MatchResult{Matched: true}, nil
 	}
 
-	// Check all expected headers are present
-	for key, expVal := range expected {
-		actVal, ok := actual[key]
+	var mismatches []Mismatch
+	for _, key := range sortedKeys(expected) {
+		actVal, ok := lookupHeader(actual, key)
 		if !ok {
-			return &MatchResult{
-				Matched: false,
-				Diff:    fmt.Sprintf("missing header: %s", key),
-			}, nil
+			mismatches = append(mismatches, Mismatch{
+				Kind: KindHeader, Path: key, Expected: expected[key], Message: "missing header",
+			})
+			continue
 		}
 
-		result, err := c.matchers["equality"].Match(expVal, actVal)
+		set, hasRule := lookupRule(rules, key)
+		if !hasRule {
+			if !headerValuesEqual(key, expected[key], actVal) {
+				mismatches = append(mismatches, Mismatch{
+					Kind: KindHeader, Path: key, Expected: expected[key], Actual: actVal, Matcher: "equality",
+					Message: fmt.Sprintf("expected %v, got %v", expected[key], actVal),
+				})
+			}
+			continue
+		}
+		setMismatches, err := c.applySet(Mismatch{Kind: KindHeader, Path: key}, set, expected[key], actVal)
 		if err != nil {
 			return nil, err
 		}
-		if !result.Matched {
-			return &MatchResult{
-				Matched: false,
-				Diff:    fmt.Sprintf("header %s: %s", key, result.Diff),
-			}, nil
+		mismatches = append(mismatches, setMismatches...)
+	}
+
+	return diffResult(mismatches), nil
+}
+
+// CompareQuery compares query parameters using the specified query matching
+// rules. A rule is applied to every actual value of its parameter; parameters
+// without a rule must have exactly the expected values.
+func (c *Comparator) CompareQuery(expected, actual map[string][]string, rules map[string]contract.MatcherSet) (*MatchResult, error) {
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var mismatches []Mismatch
+	for _, key := range keys {
+		expVals := expected[key]
+		actVals, ok := actual[key]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{
+				Kind: KindQuery, Path: key, Expected: expVals, Message: "missing query parameter",
+			})
+			continue
+		}
+
+		set, hasRule := rules[key]
+		if !hasRule {
+			if !stringSliceEqual(expVals, actVals) {
+				mismatches = append(mismatches, Mismatch{
+					Kind: KindQuery, Path: key, Expected: expVals, Actual: actVals, Matcher: "equality",
+					Message: fmt.Sprintf("expected %v, got %v", expVals, actVals),
+				})
+			}
+			continue
+		}
+
+		for i, actVal := range actVals {
+			var expVal interface{}
+			if len(expVals) > 0 {
+				expVal = expVals[min(i, len(expVals)-1)]
+			}
+			setMismatches, err := c.applySet(Mismatch{Kind: KindQuery, Path: key}, set, expVal, actVal)
+			if err != nil {
+				return nil, err
+			}
+			mismatches = append(mismatches, setMismatches...)
+		}
+	}
+
+	return diffResult(mismatches), nil
+}
+
+// CompareStatus compares response status codes using the status matching
+// rule, falling back to equality when there is no rule or it has no matchers.
+func (c *Comparator) CompareStatus(expected, actual int, rule *contract.MatcherSet) (*MatchResult, error) {
+	if rule == nil || len(rule.Matchers) == 0 {
+		if expected != actual {
+			return diffResult([]Mismatch{{
+				Kind: KindStatus, Expected: expected, Actual: actual, Matcher: "equality",
+				Message: fmt.Sprintf("expected %d, got %d", expected, actual),
+			}}), nil
+		}
+		return &MatchResult{Matched: true}, nil
+	}
+	mismatches, err := c.applySet(Mismatch{Kind: KindStatus}, *rule, expected, actual)
+	if err != nil {
+		return nil, err
+	}
+	return diffResult(mismatches), nil
+}
+
+// ComparePath compares request paths using the path matching rule, falling
+// back to equality when the rule has no matchers.
+func (c *Comparator) ComparePath(expected, actual string, rule contract.MatcherSet) (*MatchResult, error) {
+	if len(rule.Matchers) == 0 {
+		rule = equalitySet
+	}
+	mismatches, err := c.applySet(Mismatch{Kind: KindPath}, rule, expected, actual)
+	if err != nil {
+		return nil, err
+	}
+	return diffResult(mismatches), nil
+}
+
+func (c *Comparator) compareNode(path []string, expected, actual interface{}, rules bodyRules) ([]Mismatch, error) {
+	set, cascaded, hasRule := rules.resolve(path)
+	if cascaded {
+		set = withoutBounds(set)
+	}
+
+	if hasRule {
+		mismatches, matchedBy, err := c.applySetOutcome(bodyMismatch(path), set, expected, actual)
+		if err != nil || len(mismatches) > 0 || ownsStructure(set) {
+			return mismatches, err
+		}
+		// An OR set satisfied by a matcher other than type or equality,
+		// e.g. null for a nullable object, accepts the value as a whole
+		if matchedBy != "" && matchedBy != "type" && matchedBy != "equality" {
+			return nil, nil
+		}
+	}
+
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		return c.compareMap(path, exp, actual, rules, hasRule && hasMatcher(set, "equality"))
+	case []interface{}:
+		return c.compareSlice(path, exp, actual, rules, hasRule && isEachLike(set))
+	}
+
+	if hasRule {
+		return nil, nil
+	}
+	return c.applySet(bodyMismatch(path), equalitySet, expected, actual)
+}
+
+// applySet applies every matcher of set to a value and reports failures at
+// the location of loc, filled in with the values and the failed matcher. The
+// outcomes are combined with AND (the default), reporting
+// every failure, or OR, reporting the best failed attempt when none match.
+// Equality is skipped for objects and arrays, whose children are compared one
+// by one instead so that more specific rules below them still apply.
+func (c *Comparator) applySet(loc Mismatch, set contract.MatcherSet, expected, actual interface{}) ([]Mismatch, error) {
+	mismatches, _, err := c.applySetOutcome(loc, set, expected, actual)
+	return mismatches, err
+}
+
+// applySetOutcome is applySet that also returns the name of the matcher
+// that satisfied an OR set, if any.
+func (c *Comparator) applySetOutcome(loc Mismatch, set contract.MatcherSet, expected, actual interface{}) ([]Mismatch, string, error) {
+	combine := strings.ToUpper(set.Combine)
+	if combine != "" && combine != "AND" && combine != "OR" {
+		return nil, "", fmt.Errorf("%s: unsupported combine %q", loc.Location(), set.Combine)
+	}
+
+	var failures []attempt
+	deferred := false
+	for _, rule := range set.Matchers {
+		if rule.Match == "equality" && isContainer(expected) {
+			deferred = true
+			continue
 		}
+		m, ok := c.matchers[rule.Match]
+		if !ok {
+			return nil, "", fmt.Errorf("%s: unsupported matcher %q", loc.Location(), rule.Match)
+		}
+		result, err := matchRule(m, rule, expected, actual)
+		if err != nil {
+			return nil, "", err
+		}
+		if result.Matched {
+			if combine == "OR" {
+				return nil, rule.Match, nil
+			}
+			continue
+		}
+		mismatch := loc
+		mismatch.Expected = expected
+		mismatch.Actual = actual
+		mismatch.Matcher = rule.Match
+		mismatch.Message = result.Diff
+		failures = append(failures, attempt{rule: rule, mismatch: mismatch})
 	}
 
-	return &MatchResult{Matched: true}, nil
+	if len(failures) == 0 || (combine == "OR" && deferred) {
+		return nil, "", nil
+	}
+	if combine == "OR" {
+		return []Mismatch{bestAttempt(failures, expected, actual).mismatch}, "", nil
+	}
+
+	mismatches := make([]Mismatch, len(failures))
+	for i := range failures {
+		mismatches[i] = failures[i].mismatch
+	}
+	return mismatches, "", nil
+}
+
+// attempt is a failed matcher of a set.
+type attempt struct {
+	rule     contract.Matcher
+	mismatch Mismatch
+}
+
+// valueTypes lists the JSON types the built-in value matchers can accept.
+// Matchers not listed here accept any type.
+var valueTypes = map[string][]string{
+	"number":    {"number"},
+	"integer":   {"number"},
+	"decimal":   {"number"},
+	"regex":     {"string"},
+	"date":      {"string"},
+	"time":      {"string"},
+	"timestamp": {"string"},
+	"include":   {"string"},
+	"null":      {"null"},
+	"boolean":   {"boolean"},
+	"semver":    {"string"},
+}
+
+// bestAttempt picks the failure to report when no matcher of an OR set
+// matched: the first matcher that accepts the actual JSON type, since it
+// failed on the value rather than on the type, or else the first failure.
+func bestAttempt(failures []attempt, expected, actual interface{}) attempt {
+	actType := jsonType(actual)
+	for _, f := range failures {
+		types, ok := valueTypes[f.rule.Match]
+		if !ok && (f.rule.Match == "type" || f.rule.Match == "equality") {
+			types = []string{jsonType(expected)}
+			ok = true
+		}
+		if !ok {
+			return f
+		}
+		for _, t := range types {
+			if t == actType {
+				return f
+			}
+		}
+	}
+	return failures[0]
+}
+
+// matchRule runs m, passing it the rule attributes if it accepts them.
+func matchRule(m Matcher, rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	if rm, ok := m.(RuleMatcher); ok {
+		return rm.MatchRule(rule, expected, actual)
+	}
+	return m.Match(expected, actual)
+}
+
+// hasMatcher reports whether set contains a matcher with the given name.
+func hasMatcher(set contract.MatcherSet, name string) bool {
+	for _, rule := range set.Matchers {
+		if rule.Match == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ownsStructure reports whether set contains a matcher that compares the
+// contents of an object or array itself, so its expected fields and
+// elements must not also be compared one by one.
+func ownsStructure(set contract.MatcherSet) bool {
+	return hasMatcher(set, "arrayContains") || hasMatcher(set, "eachKey") || hasMatcher(set, "eachValue")
+}
+
+// withoutBounds returns a copy of set without min/max. The bounds of a rule
+// only apply to the array it names, not to arrays nested below it that the
+// rule cascades to.
+func withoutBounds(set contract.MatcherSet) contract.MatcherSet {
+	matchers := make([]contract.Matcher, len(set.Matchers))
+	for i, rule := range set.Matchers {
+		rule.Min = nil
+		rule.Max = nil
+		matchers[i] = rule
+	}
+	set.Matchers = matchers
+	return set
+}
+
+// isEachLike reports whether set compares arrays element by element against
+// the first expected element rather than position by position.
+func isEachLike(set contract.MatcherSet) bool {
+	for _, rule := range set.Matchers {
+		if rule.Match == "type" || rule.Match == "notEmpty" || rule.Min != nil || rule.Max != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// compareMap compares the expected fields of an object. Unexpected fields are
+// ignored unless strict, which an explicit equality rule asks for.
+func (c *Comparator) compareMap(path []string, expected map[string]interface{}, actual interface{}, rules bodyRules, strict bool) ([]Mismatch, error) {
+	act, ok := actual.(map[string]interface{})
+	if !ok {
+		mismatch := bodyMismatch(path)
+		mismatch.Expected = expected
+		mismatch.Actual = actual
+		mismatch.Message = fmt.Sprintf("expected object, got %s", jsonType(actual))
+		return []Mismatch{mismatch}, nil
+	}
+
+	var mismatches []Mismatch
+	for _, key := range sortedKeys(expected) {
+		childPath := appendPath(path, key)
+		actVal, ok := act[key]
+		if !ok {
+			mismatch := bodyMismatch(childPath)
+			mismatch.Expected = expected[key]
+			mismatch.Message = "missing field"
+			mismatches = append(mismatches, mismatch)
+			continue
+		}
+		childMismatches, err := c.compareNode(childPath, expected[key], actVal, rules)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, childMismatches...)
+	}
+
+	if strict {
+		keys := make([]string, 0, len(act))
+		for key := range act {
+			if _, ok := expected[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			mismatch := bodyMismatch(appendPath(path, key))
+			mismatch.Actual = act[key]
+			mismatch.Matcher = "equality"
+			mismatch.Message = "unexpected field"
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches, nil
+}
+
+// compareSlice compares arrays position by position, or with eachLike every
+// actual element against the first expected element, in which case the
+// array length is left to the min/max of the rule.
+func (c *Comparator) compareSlice(path []string, expected []interface{}, actual interface{}, rules bodyRules, eachLike bool) ([]Mismatch, error) {
+	act, ok := actual.([]interface{})
+	if !ok {
+		mismatch := bodyMismatch(path)
+		mismatch.Expected = expected
+		mismatch.Actual = actual
+		mismatch.Message = fmt.Sprintf("expected array, got %s", jsonType(actual))
+		return []Mismatch{mismatch}, nil
+	}
+
+	if eachLike {
+		if len(expected) == 0 {
+			return nil, nil
+		}
+		var mismatches []Mismatch
+		for i := range act {
+			childMismatches, err := c.compareNode(appendPath(path, indexToken(i)), expected[0], act[i], rules)
+			if err != nil {
+				return nil, err
+			}
+			mismatches = append(mismatches, childMismatches...)
+		}
+		return mismatches, nil
+	}
+
+	if len(expected) != len(act) {
+		mismatch := bodyMismatch(path)
+		mismatch.Expected = expected
+		mismatch.Actual = actual
+		mismatch.Message = fmt.Sprintf("expected array length %d, got %d", len(expected), len(act))
+		return []Mismatch{mismatch}, nil
+	}
+
+	var mismatches []Mismatch
+	for i := range expected {
+		childMismatches, err := c.compareNode(appendPath(path, indexToken(i)), expected[i], act[i], rules)
+		if err != nil {
+			return nil, err
+		}
+		mismatches = append(mismatches, childMismatches...)
+	}
+	return mismatches, nil
+}
+
+// diffResult builds a MatchResult from a list of mismatches.
+func diffResult(mismatches []Mismatch) *MatchResult {
+	if len(mismatches) > 0 {
+		return &MatchResult{Matched: false, Diff: JoinMismatches(mismatches), Mismatches: mismatches}
+	}
+	return &MatchResult{Matched: true}
+}
+
+// messageResult builds a MatchResult from plain diff messages, which a
+// Comparator reports as a single mismatch of the matcher that produced them.
+func messageResult(diffs []string) *MatchResult {
+	if len(diffs) > 0 {
+		return &MatchResult{Matched: false, Diff: strings.Join(diffs, "; ")}
+	}
+	return &MatchResult{Matched: true}
+}
+
+// bodyMismatch returns a body mismatch located at path.
+func bodyMismatch(path []string) Mismatch {
+	return Mismatch{Kind: KindBody, Path: formatPath(path)}
+}
+
+// lookupRule finds the rule for a header name, ignoring case.
+func lookupRule(rules map[string]contract.MatcherSet, name string) (contract.MatcherSet, bool) {
+	if set, ok := rules[name]; ok {
+		return set, true
+	}
+	for key, set := range rules {
+		if strings.EqualFold(key, name) {
+			return set, true
+		}
+	}
+	return contract.MatcherSet{}, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringSliceEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// appendPath returns a copy of path with token appended, so sibling paths
+// never share a backing array.
+func appendPath(path []string, token string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, token)
+}
+
+func isContainer(v interface{}) bool {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+// jsonType returns the JSON type name of a decoded JSON value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number, float64, float32, int, int64, int32:
+		return "number"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
 }
@@ -0,0 +1,97 @@
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// ContentTypeMatcher matches bodies whose detected MIME type is the rule's
+// value.
+type ContentTypeMatcher struct{}
+
+// NewContentTypeMatcher creates a new ContentTypeMatcher.
+func NewContentTypeMatcher() *ContentTypeMatcher {
+	return &ContentTypeMatcher{}
+}
+
+// Name returns "contentType".
+func (m *ContentTypeMatcher) Name() string {
+	return "contentType"
+}
+
+// Match always fails because a contentType matcher needs the rule's value.
+func (m *ContentTypeMatcher) Match(_, _ interface{}) (*MatchResult, error) {
+	return nil, fmt.Errorf("contentType matcher requires a value")
+}
+
+// MatchRule sniffs the MIME type of actual, which must be a string or raw
+// bytes, and compares it with rule.Value. Plain text sniffing cannot tell
+// formats such as CSV apart, so any text/* type accepts plain text.
+func (m *ContentTypeMatcher) MatchRule(rule contract.Matcher, _, actual interface{}) (*MatchResult, error) {
+	value, _ := rule.Value.(string)
+	want := MediaType(value)
+	if want == "" {
+		return nil, fmt.Errorf("contentType matcher requires a value")
+	}
+
+	var data []byte
+	switch a := actual.(type) {
+	case string:
+		data = []byte(a)
+	case []byte:
+		data = a
+	default:
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected %s content, got %s", want, jsonType(actual)),
+		}, nil
+	}
+
+	detected := DetectContentType(data)
+	switch {
+	case detected == want:
+	case detected == "text/plain" && strings.HasPrefix(want, "text/"):
+	case isXML(detected) && isXML(want):
+	default:
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected %s content, detected %s", want, detected),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// DetectContentType sniffs the media type of data. It extends
+// http.DetectContentType, which reports JSON as plain text.
+func DetectContentType(data []byte) string {
+	detected := MediaType(http.DetectContentType(data))
+	if detected == "text/plain" && json.Valid(data) {
+		return "application/json"
+	}
+	return detected
+}
+
+// MediaType returns the lower-cased media type of a Content-Type value
+// without its parameters.
+func MediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	}
+	return mediaType
+}
+
+// IsJSONMediaType reports whether a media type is JSON, including
+// structured syntax types such as application/hal+json.
+func IsJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isXML(mediaType string) bool {
+	return mediaType == "text/xml" || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml")
+}
@@ -0,0 +1,203 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// DateTimeMatcher matches strings against a date and/or time format written
+// as a Java DateTimeFormatter pattern (e.g. "yyyy-MM-dd'T'HH:mm:ss.SSSX"),
+// as used by Pact's date, time and timestamp matchers.
+type DateTimeMatcher struct {
+	name          string
+	defaultLayout string
+}
+
+// NewDateMatcher creates a matcher for "date" rules. Without a format it
+// expects an ISO 8601 date.
+func NewDateMatcher() *DateTimeMatcher {
+	return &DateTimeMatcher{name: "date", defaultLayout: "2006-01-02"}
+}
+
+// NewTimeMatcher creates a matcher for "time" rules. Without a format it
+// expects an ISO 8601 time.
+func NewTimeMatcher() *DateTimeMatcher {
+	return &DateTimeMatcher{name: "time", defaultLayout: "15:04:05"}
+}
+
+// NewTimestampMatcher creates a matcher for "timestamp" rules. Without a
+// format it expects an RFC 3339 timestamp.
+func NewTimestampMatcher() *DateTimeMatcher {
+	return &DateTimeMatcher{name: "timestamp", defaultLayout: time.RFC3339Nano}
+}
+
+// Name returns "date", "time" or "timestamp".
+func (m *DateTimeMatcher) Name() string {
+	return m.name
+}
+
+// Match checks actual against the default format.
+func (m *DateTimeMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	return m.MatchRule(contract.Matcher{Match: m.name}, expected, actual)
+}
+
+// MatchRule checks that actual is a string in the rule's format.
+func (m *DateTimeMatcher) MatchRule(rule contract.Matcher, _, actual interface{}) (*MatchResult, error) {
+	layout := m.defaultLayout
+	format := "ISO 8601"
+	if rule.Format != "" {
+		var err error
+		layout, err = JavaLayout(rule.Format)
+		if err != nil {
+			return nil, err
+		}
+		format = rule.Format
+	}
+
+	s, ok := actual.(string)
+	if !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a %s string in format %q, got %s", m.name, format, jsonType(actual)),
+		}, nil
+	}
+	if _, err := parseTime(layout, s); err != nil {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected %q to be a %s in format %q", s, m.name, format),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// parseTime parses s with layout. Java's single-letter offset patterns ("X"
+// and "x") print minutes when they are not zero, so if parsing fails, offsets
+// with hours only are retried as hours and minutes (e.g. "+0530").
+func parseTime(layout, s string) (time.Time, error) {
+	t, err := time.Parse(layout, s)
+	if err == nil {
+		return t, nil
+	}
+	if widened := widenHourOffsets(layout); widened != layout {
+		if t, widenedErr := time.Parse(widened, s); widenedErr == nil {
+			return t, nil
+		}
+	}
+	return t, err
+}
+
+// widenHourOffsets replaces the hour-only offset elements "Z07" and "-07" of
+// a Go layout with "Z0700" and "-0700".
+func widenHourOffsets(layout string) string {
+	var b strings.Builder
+	for i := 0; i < len(layout); i++ {
+		b.WriteByte(layout[i])
+		if (layout[i] != 'Z' && layout[i] != '-') || !strings.HasPrefix(layout[i+1:], "07") {
+			continue
+		}
+		rest := layout[i+3:]
+		b.WriteString("07")
+		i += 2
+		if !strings.HasPrefix(rest, "00") && !strings.HasPrefix(rest, ":") {
+			b.WriteString("00")
+		}
+	}
+	return b.String()
+}
+
+// javaLayoutTokens maps Java DateTimeFormatter pattern letters, by run
+// length, to Go layout elements. A length of 0 is the fallback for any run
+// length without its own entry.
+var javaLayoutTokens = map[byte]map[int]string{
+	'y': {2: "06", 0: "2006"},
+	'u': {2: "06", 0: "2006"},
+	'M': {1: "1", 2: "01", 3: "Jan", 0: "January"},
+	'd': {1: "2", 0: "02"},
+	'D': {0: "002"},
+	'H': {0: "15"},
+	'h': {1: "3", 0: "03"},
+	'm': {1: "4", 0: "04"},
+	's': {1: "5", 0: "05"},
+	'a': {0: "PM"},
+	'E': {4: "Monday", 0: "Mon"},
+	'X': {1: "Z07", 2: "Z0700", 0: "Z07:00"},
+	'x': {1: "-07", 2: "-0700", 0: "-07:00"},
+	'Z': {0: "-0700"},
+	'z': {0: "MST"},
+}
+
+// JavaLayout translates a Java DateTimeFormatter pattern into a Go time
+// layout. Text in single quotes is copied literally and two single quotes in
+// a row stand for one.
+func JavaLayout(pattern string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); {
+		ch := pattern[i]
+
+		if ch == '\'' {
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				b.WriteByte('\'')
+				i += 2
+				continue
+			}
+			i++
+			for {
+				if i >= len(pattern) {
+					return "", fmt.Errorf("invalid date format %q: unterminated quote", pattern)
+				}
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						b.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteByte(pattern[i])
+				i++
+			}
+			continue
+		}
+
+		if !isASCIILetter(ch) {
+			b.WriteByte(ch)
+			i++
+			continue
+		}
+
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == ch {
+			n++
+		}
+		i += n
+
+		// Fractions of a second become the digits after Go's "." or ",",
+		// which cannot parse them without a separator
+		if ch == 'S' {
+			if layout := b.String(); layout == "" || !strings.ContainsAny(layout[len(layout)-1:], ".,") {
+				return "", fmt.Errorf("invalid date format %q: unsupported fraction of second without a preceding '.' or ','", pattern)
+			}
+			b.WriteString(strings.Repeat("0", n))
+			continue
+		}
+
+		tokens, ok := javaLayoutTokens[ch]
+		if !ok {
+			return "", fmt.Errorf("invalid date format %q: unsupported pattern letter %q", pattern, ch)
+		}
+		token, ok := tokens[n]
+		if !ok {
+			token = tokens[0]
+		}
+		b.WriteString(token)
+	}
+	return b.String(), nil
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
@@ -0,0 +1,144 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// EachKeyMatcher matches objects whose every key satisfies the rule's nested
+// matchers.
+type EachKeyMatcher struct {
+	comparator *Comparator
+}
+
+// NewEachKeyMatcher creates a new EachKeyMatcher that applies nested rules
+// with c.
+func NewEachKeyMatcher(c *Comparator) *EachKeyMatcher {
+	return &EachKeyMatcher{comparator: c}
+}
+
+// Name returns "eachKey".
+func (m *EachKeyMatcher) Name() string {
+	return "eachKey"
+}
+
+// Match always fails because eachKey needs the rule's nested matchers.
+func (m *EachKeyMatcher) Match(_, _ interface{}) (*MatchResult, error) {
+	return nil, fmt.Errorf("eachKey matcher requires rules")
+}
+
+// MatchRule checks every key of actual against rule.Rules.
+func (m *EachKeyMatcher) MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	if len(rule.Rules) == 0 {
+		return nil, fmt.Errorf("eachKey matcher requires rules")
+	}
+	act, ok := actual.(map[string]interface{})
+	if !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected object, got %s", jsonType(actual)),
+		}, nil
+	}
+
+	var example interface{}
+	if exp, ok := expected.(map[string]interface{}); ok && len(exp) > 0 {
+		example = sortedKeys(exp)[0]
+	}
+
+	set := contract.MatcherSet{Matchers: rule.Rules}
+	var diffs []string
+	for _, key := range sortedKeys(act) {
+		mismatches, err := m.comparator.applySet(Mismatch{Kind: KindBody}, set, example, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, mismatch := range mismatches {
+			diffs = append(diffs, fmt.Sprintf("key %q: %s", key, mismatch.Message))
+		}
+	}
+	return messageResult(diffs), nil
+}
+
+// EachValueMatcher matches objects and arrays whose every value satisfies the
+// rule's nested matchers.
+type EachValueMatcher struct {
+	comparator *Comparator
+}
+
+// NewEachValueMatcher creates a new EachValueMatcher that applies nested
+// rules with c.
+func NewEachValueMatcher(c *Comparator) *EachValueMatcher {
+	return &EachValueMatcher{comparator: c}
+}
+
+// Name returns "eachValue".
+func (m *EachValueMatcher) Name() string {
+	return "eachValue"
+}
+
+// Match always fails because eachValue needs the rule's nested matchers.
+func (m *EachValueMatcher) Match(_, _ interface{}) (*MatchResult, error) {
+	return nil, fmt.Errorf("eachValue matcher requires rules")
+}
+
+// MatchRule compares every value of actual against an example value using
+// rule.Rules. The example is rule.Value, or else the first expected value.
+func (m *EachValueMatcher) MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	if len(rule.Rules) == 0 {
+		return nil, fmt.Errorf("eachValue matcher requires rules")
+	}
+
+	var labels []string
+	var values []interface{}
+	switch act := actual.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(act) {
+			labels = append(labels, fmt.Sprintf("[%q]", key))
+			values = append(values, act[key])
+		}
+	case []interface{}:
+		for i, v := range act {
+			labels = append(labels, indexToken(i))
+			values = append(values, v)
+		}
+	default:
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected object or array, got %s", jsonType(actual)),
+		}, nil
+	}
+
+	example := rule.Value
+	if example == nil {
+		example = firstValue(expected)
+	}
+
+	rules := map[string]contract.MatcherSet{rootToken: {Matchers: rule.Rules}}
+	var diffs []string
+	for i, v := range values {
+		result, err := m.comparator.CompareBody(example, v, rules)
+		if err != nil {
+			return nil, err
+		}
+		if !result.Matched {
+			diffs = append(diffs, fmt.Sprintf("value %s: %s", labels[i], result.Diff))
+		}
+	}
+	return messageResult(diffs), nil
+}
+
+// firstValue returns the first value of an object (by key) or array.
+func firstValue(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		if len(c) > 0 {
+			return c[sortedKeys(c)[0]]
+		}
+	case []interface{}:
+		if len(c) > 0 {
+			return c[0]
+		}
+	}
+	return nil
+}
@@ -23,6 +23,8 @@ func (m *EqualityMatcher) Match(expected, actual interface{}) (*MatchResult, err
 	if deepEqual(expected, actual) {
 		return &MatchResult{Matched: true}, nil
 	}
+	expected = normalizeNumber(expected)
+	actual = normalizeNumber(actual)
 	return &MatchResult{
 		Matched: false,
 		Diff:    fmt.Sprintf("expected %v (%T), got %v (%T)", expected, expected, actual, actual),
@@ -30,6 +32,9 @@ func (m *EqualityMatcher) Match(expected, actual interface{}) (*MatchResult, err
 }
 
 func deepEqual(expected, actual interface{}) bool {
+	expected = normalizeNumber(expected)
+	actual = normalizeNumber(actual)
+
 	if expected == nil && actual == nil {
 		return true
 	}
@@ -0,0 +1,95 @@
+package matcher
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// lookupHeader finds a header value by name, ignoring case.
+func lookupHeader(headers map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := headers[name]; ok {
+		return value, true
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// HeaderValue formats a contract header value as sent on the wire. Lists,
+// which Pact v4 uses for headers with several values, are joined with ", ".
+func HeaderValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprintf("%v", item)
+		}
+		return strings.Join(items, ", ")
+	case []string:
+		return strings.Join(v, ", ")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// headerValuesEqual compares header values without a matching rule.
+// Comma-separated values are compared item by item, ignoring the spacing
+// around commas. Content-Type values are compared by media type and the
+// expected parameters, so parameter order, the case of the charset and extra
+// actual parameters do not matter.
+func headerValuesEqual(name string, expected, actual interface{}) bool {
+	exp := HeaderValue(expected)
+	act := HeaderValue(actual)
+
+	if strings.EqualFold(name, "Content-Type") {
+		if equal, ok := contentTypesEqual(exp, act); ok {
+			return equal
+		}
+	}
+	return stringSliceEqual(splitHeaderValues(exp), splitHeaderValues(act))
+}
+
+// contentTypesEqual compares two Content-Type values. Every expected
+// parameter must be present in actual with the same value. ok is false if
+// either cannot be parsed.
+func contentTypesEqual(expected, actual string) (equal, ok bool) {
+	expType, expParams, err := mime.ParseMediaType(expected)
+	if err != nil {
+		return false, false
+	}
+	actType, actParams, err := mime.ParseMediaType(actual)
+	if err != nil {
+		return false, false
+	}
+	if expType != actType {
+		return false, true
+	}
+	for key, value := range expParams {
+		actValue, found := actParams[key]
+		if !found {
+			return false, true
+		}
+		if key == "charset" {
+			value = strings.ToLower(value)
+			actValue = strings.ToLower(actValue)
+		}
+		if value != actValue {
+			return false, true
+		}
+	}
+	return true, true
+}
+
+// splitHeaderValues splits a comma-separated header value into its items.
+func splitHeaderValues(value string) []string {
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
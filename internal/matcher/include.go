@@ -0,0 +1,54 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// IncludeMatcher matches strings that contain the rule's value.
+type IncludeMatcher struct{}
+
+// NewIncludeMatcher creates a new IncludeMatcher.
+func NewIncludeMatcher() *IncludeMatcher {
+	return &IncludeMatcher{}
+}
+
+// Name returns "include".
+func (m *IncludeMatcher) Name() string {
+	return "include"
+}
+
+// Match checks that actual contains expected.
+func (m *IncludeMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	return m.MatchRule(contract.Matcher{Match: "include"}, expected, actual)
+}
+
+// MatchRule checks that actual contains rule.Value, or expected when the rule
+// has no value.
+func (m *IncludeMatcher) MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	value := rule.Value
+	if value == nil {
+		value = expected
+	}
+	substr, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("include matcher requires a string value, got %s", jsonType(value))
+	}
+
+	s, ok := actual.(string)
+	if !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a string including %q, got %s", substr, jsonType(actual)),
+		}, nil
+	}
+	if !strings.Contains(s, substr) {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected %q to include %q", s, substr),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
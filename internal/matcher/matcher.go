@@ -1,10 +1,15 @@
 // Package matcher provides matching rule implementations for contract verification.
 package matcher
 
+import "github.com/jt-chihara/yakusoku/internal/contract"
+
 // MatchResult represents the result of a match operation.
 type MatchResult struct {
 	Matched bool
 	Diff    string
+	// Mismatches holds the individual differences behind Diff when the
+	// result comes from a Comparator.
+	Mismatches []Mismatch
 }
 
 // Matcher is the interface that all matchers must implement.
@@ -15,3 +20,12 @@ type Matcher interface {
 	// Match compares expected and actual values according to the matcher's rules.
 	Match(expected, actual interface{}) (*MatchResult, error)
 }
+
+// RuleMatcher is implemented by matchers that need the attributes of the
+// matching rule they were selected by (e.g. min/max, regex, format).
+type RuleMatcher interface {
+	Matcher
+
+	// MatchRule compares expected and actual values according to rule.
+	MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error)
+}
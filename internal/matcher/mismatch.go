@@ -0,0 +1,55 @@
+package matcher
+
+import "strings"
+
+// Mismatch kinds name the part of a request or response a mismatch was
+// found in.
+const (
+	KindStatus = "status"
+	KindPath   = "path"
+	KindQuery  = "query"
+	KindHeader = "header"
+	KindBody   = "body"
+)
+
+// Mismatch describes a single difference between an expected and an actual
+// value.
+type Mismatch struct {
+	// Kind is one of the Kind constants.
+	Kind string `json:"kind"`
+	// Path is the JSON path of a body mismatch or the name of a header or
+	// query parameter.
+	Path     string      `json:"path,omitempty"`
+	Expected interface{} `json:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty"`
+	// Matcher is the name of the matcher that failed, if any.
+	Matcher string `json:"matcher,omitempty"`
+	Message string `json:"message"`
+}
+
+// Location returns where the mismatch was found, e.g. "$.items[0].id" or
+// "header Content-Type".
+func (m Mismatch) Location() string {
+	switch m.Kind {
+	case KindBody:
+		return m.Path
+	case KindQuery, KindHeader:
+		return m.Kind + " " + m.Path
+	default:
+		return m.Kind
+	}
+}
+
+// String returns the location and message of the mismatch.
+func (m Mismatch) String() string {
+	return m.Location() + ": " + m.Message
+}
+
+// JoinMismatches renders mismatches as a single "; " separated string.
+func JoinMismatches(mismatches []Mismatch) string {
+	parts := make([]string, len(mismatches))
+	for i := range mismatches {
+		parts[i] = mismatches[i].String()
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,59 @@
+package matcher
+
+import "fmt"
+
+// NotEmptyMatcher matches values of the expected JSON type that are not
+// null, an empty string, an empty array or an empty object.
+type NotEmptyMatcher struct{}
+
+// NewNotEmptyMatcher creates a new NotEmptyMatcher.
+func NewNotEmptyMatcher() *NotEmptyMatcher {
+	return &NotEmptyMatcher{}
+}
+
+// Name returns "notEmpty".
+func (m *NotEmptyMatcher) Name() string {
+	return "notEmpty"
+}
+
+// Match checks that actual has the type of expected and is not empty.
+func (m *NotEmptyMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	if expected != nil && jsonType(expected) != jsonType(actual) {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected non-empty %s, got %s (%v)", jsonType(expected), jsonType(actual), actual),
+		}, nil
+	}
+
+	empty := false
+	switch v := actual.(type) {
+	case nil:
+		empty = true
+	case string:
+		empty = v == ""
+	case []interface{}:
+		empty = len(v) == 0
+	case map[string]interface{}:
+		empty = len(v) == 0
+	}
+	if empty {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a non-empty value, got %s", formatEmpty(actual)),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+func formatEmpty(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return `""`
+	case []interface{}:
+		return "[]"
+	default:
+		return "{}"
+	}
+}
@@ -0,0 +1,27 @@
+package matcher
+
+import "fmt"
+
+// NullMatcher matches JSON null.
+type NullMatcher struct{}
+
+// NewNullMatcher creates a new NullMatcher.
+func NewNullMatcher() *NullMatcher {
+	return &NullMatcher{}
+}
+
+// Name returns "null".
+func (m *NullMatcher) Name() string {
+	return "null"
+}
+
+// Match checks that actual is null.
+func (m *NullMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	if actual != nil {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected null, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
@@ -0,0 +1,115 @@
+package matcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberMatcher matches any JSON number.
+type NumberMatcher struct{}
+
+// NewNumberMatcher creates a new NumberMatcher.
+func NewNumberMatcher() *NumberMatcher {
+	return &NumberMatcher{}
+}
+
+// Name returns "number".
+func (m *NumberMatcher) Name() string {
+	return "number"
+}
+
+// Match checks that actual is a number.
+func (m *NumberMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	if _, ok := numberText(actual); !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a number, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// IntegerMatcher matches JSON numbers written without a fraction or exponent.
+type IntegerMatcher struct{}
+
+// NewIntegerMatcher creates a new IntegerMatcher.
+func NewIntegerMatcher() *IntegerMatcher {
+	return &IntegerMatcher{}
+}
+
+// Name returns "integer".
+func (m *IntegerMatcher) Name() string {
+	return "integer"
+}
+
+// Match checks that actual is an integer number.
+func (m *IntegerMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	text, ok := numberText(actual)
+	if !ok || strings.ContainsAny(text, ".eE") {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected an integer, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// DecimalMatcher matches JSON numbers written with a decimal point.
+type DecimalMatcher struct{}
+
+// NewDecimalMatcher creates a new DecimalMatcher.
+func NewDecimalMatcher() *DecimalMatcher {
+	return &DecimalMatcher{}
+}
+
+// Name returns "decimal".
+func (m *DecimalMatcher) Name() string {
+	return "decimal"
+}
+
+// Match checks that actual is a decimal number.
+func (m *DecimalMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	text, ok := numberText(actual)
+	if !ok || !strings.Contains(text, ".") {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a decimal, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// numberText returns the textual form of a number. json.Number keeps the
+// token as it appeared in the JSON document; other numeric types are
+// formatted in their shortest form, so a whole float64 reads as an integer.
+func numberText(v interface{}) (string, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.String(), true
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64), true
+	case float32:
+		return strconv.FormatFloat(float64(n), 'f', -1, 32), true
+	case int:
+		return strconv.Itoa(n), true
+	case int64:
+		return strconv.FormatInt(n, 10), true
+	case int32:
+		return strconv.FormatInt(int64(n), 10), true
+	default:
+		return "", false
+	}
+}
+
+// normalizeNumber converts a json.Number to float64 so that numbers decoded
+// with json.Decoder.UseNumber compare equal to ones decoded without it.
+func normalizeNumber(v interface{}) interface{} {
+	if n, ok := v.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
@@ -0,0 +1,165 @@
+package matcher
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// rootToken is the first token of every path.
+const rootToken = "$"
+
+// ParsePath splits a Pact matching rule or generator path such as
+// "$.items[*].id" or "$['first name']" into tokens. Array indices keep their
+// brackets ("[0]", "[*]") so they can be told apart from field names. The
+// "$.body" prefix used by Pact v2 contracts is treated as the body root.
+func ParsePath(p string) ([]string, error) {
+	if !strings.HasPrefix(p, rootToken) {
+		return nil, fmt.Errorf("invalid matching rule path %q: must start with $", p)
+	}
+
+	tokens := []string{rootToken}
+	rest := p[1:]
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid matching rule path %q: unterminated ['", p)
+			}
+			tokens = append(tokens, rest[2:end])
+			rest = rest[end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid matching rule path %q: unterminated [", p)
+			}
+			index := rest[1:end]
+			if index != "*" {
+				if _, err := strconv.Atoi(index); err != nil {
+					return nil, fmt.Errorf("invalid matching rule path %q: bad index %q", p, index)
+				}
+			}
+			tokens = append(tokens, "["+index+"]")
+			rest = rest[end+1:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid matching rule path %q: empty field name", p)
+			}
+			tokens = append(tokens, rest[:end])
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("invalid matching rule path %q: unexpected %q", p, rest)
+		}
+	}
+
+	// Pact v2 rooted body rules at "$.body".
+	if len(tokens) > 1 && tokens[1] == "body" {
+		tokens = append([]string{rootToken}, tokens[2:]...)
+	}
+
+	return tokens, nil
+}
+
+// pathWeight returns how specifically rule applies to the actual path, or 0 if
+// it does not apply. A rule applies to the path it names and to everything
+// below it. Exact tokens weigh 2 and wildcards 1; the weight is their product,
+// so the most specific rule has the highest weight.
+func pathWeight(rule, actual []string) int {
+	if len(rule) > len(actual) {
+		return 0
+	}
+
+	weight := 1
+	for i, token := range rule {
+		switch {
+		case token == actual[i]:
+			weight *= 2
+		case token == "*" || token == "[*]":
+			weight *= 1
+		default:
+			return 0
+		}
+	}
+	return weight
+}
+
+// formatPath renders path tokens back into a JSON path like "$.items[0].id".
+func formatPath(tokens []string) string {
+	var b strings.Builder
+	for i, token := range tokens {
+		switch {
+		case i == 0:
+			b.WriteString(token)
+		case strings.HasPrefix(token, "["):
+			b.WriteString(token)
+		case strings.ContainsAny(token, ". []'"):
+			b.WriteString("['" + token + "']")
+		default:
+			b.WriteString("." + token)
+		}
+	}
+	return b.String()
+}
+
+// indexToken returns the path token for an array index.
+func indexToken(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// bodyRule is a parsed body matching rule.
+type bodyRule struct {
+	path []string
+	set  contract.MatcherSet
+}
+
+// bodyRules resolves the matcher set that applies to a body path.
+type bodyRules []bodyRule
+
+// newBodyRules parses body matching rules keyed by Pact path.
+func newBodyRules(rules map[string]contract.MatcherSet) (bodyRules, error) {
+	result := make(bodyRules, 0, len(rules))
+	for p, set := range rules {
+		tokens, err := ParsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, bodyRule{path: tokens, set: set})
+	}
+	// Sort for deterministic tie-breaking between equally weighted rules.
+	sort.Slice(result, func(i, j int) bool {
+		return formatPath(result[i].path) < formatPath(result[j].path)
+	})
+	return result, nil
+}
+
+// resolve returns the matcher set registered for the most specific rule
+// path that applies to path. Ties are broken in favour of the longer path.
+// cascaded reports whether the rule names an ancestor of path rather than
+// path itself.
+func (r bodyRules) resolve(path []string) (set contract.MatcherSet, cascaded, ok bool) {
+	best := -1
+	bestWeight := 0
+	for i := range r {
+		weight := pathWeight(r[i].path, path)
+		if weight == 0 {
+			continue
+		}
+		if weight > bestWeight || (weight == bestWeight && len(r[i].path) > len(r[best].path)) {
+			best = i
+			bestWeight = weight
+		}
+	}
+	if best < 0 {
+		return contract.MatcherSet{}, false, false
+	}
+	return r[best].set, len(r[best].path) < len(path), true
+}
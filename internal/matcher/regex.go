@@ -0,0 +1,55 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// RegexMatcher matches values against the regular expression of the rule.
+// The expression must match the whole value.
+type RegexMatcher struct{}
+
+// NewRegexMatcher creates a new RegexMatcher.
+func NewRegexMatcher() *RegexMatcher {
+	return &RegexMatcher{}
+}
+
+// Name returns "regex".
+func (m *RegexMatcher) Name() string {
+	return "regex"
+}
+
+// Match always fails because a regex matcher needs the rule's expression.
+func (m *RegexMatcher) Match(_, _ interface{}) (*MatchResult, error) {
+	return nil, fmt.Errorf("regex matcher requires a regex")
+}
+
+// MatchRule checks that actual matches rule.Regex.
+func (m *RegexMatcher) MatchRule(rule contract.Matcher, _, actual interface{}) (*MatchResult, error) {
+	if rule.Regex == "" {
+		return nil, fmt.Errorf("regex matcher requires a regex")
+	}
+	re, err := regexp.Compile(`^(?:` + rule.Regex + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", rule.Regex, err)
+	}
+
+	switch actual.(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a value matching %q, got %s", rule.Regex, jsonType(actual)),
+		}, nil
+	}
+
+	s := fmt.Sprintf("%v", actual)
+	if !re.MatchString(s) {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected %q to match %q", s, rule.Regex),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
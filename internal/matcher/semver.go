@@ -0,0 +1,36 @@
+package matcher
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// semverPattern matches a semantic version as defined by semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// SemverMatcher matches strings that are semantic versions.
+type SemverMatcher struct{}
+
+// NewSemverMatcher creates a new SemverMatcher.
+func NewSemverMatcher() *SemverMatcher {
+	return &SemverMatcher{}
+}
+
+// Name returns "semver".
+func (m *SemverMatcher) Name() string {
+	return "semver"
+}
+
+// Match checks that actual is a semantic version string.
+func (m *SemverMatcher) Match(_, actual interface{}) (*MatchResult, error) {
+	s, ok := actual.(string)
+	if !ok || !semverPattern.MatchString(s) {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a semantic version, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
@@ -0,0 +1,91 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// statusClasses maps the named status classes of Pact's statusCode matcher
+// to the inclusive range of codes they cover.
+var statusClasses = map[string][2]int{
+	"information": {100, 199},
+	"success":     {200, 299},
+	"redirect":    {300, 399},
+	"clientError": {400, 499},
+	"serverError": {500, 599},
+	"nonError":    {100, 399},
+	"error":       {400, 599},
+}
+
+// StatusCodeMatcher matches HTTP status codes against a named class (e.g.
+// "success", "clientError") or an explicit list of codes.
+type StatusCodeMatcher struct{}
+
+// NewStatusCodeMatcher creates a new StatusCodeMatcher.
+func NewStatusCodeMatcher() *StatusCodeMatcher {
+	return &StatusCodeMatcher{}
+}
+
+// Name returns "statusCode".
+func (m *StatusCodeMatcher) Name() string {
+	return "statusCode"
+}
+
+// Match checks that actual equals the expected status code.
+func (m *StatusCodeMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	return m.MatchRule(contract.Matcher{Match: "statusCode", Status: []interface{}{expected}}, expected, actual)
+}
+
+// MatchRule checks that actual is in the status class or list of rule.Status.
+func (m *StatusCodeMatcher) MatchRule(rule contract.Matcher, _, actual interface{}) (*MatchResult, error) {
+	code, ok := toInt(actual)
+	if !ok {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected a status code, got %s (%v)", jsonType(actual), actual),
+		}, nil
+	}
+
+	switch status := rule.Status.(type) {
+	case string:
+		bounds, ok := statusClasses[status]
+		if !ok {
+			return nil, fmt.Errorf("unknown status class %q", status)
+		}
+		if code < bounds[0] || code > bounds[1] {
+			return &MatchResult{
+				Matched: false,
+				Diff:    fmt.Sprintf("expected a %s status, got %d", status, code),
+			}, nil
+		}
+		return &MatchResult{Matched: true}, nil
+	case []interface{}:
+		for _, s := range status {
+			if expected, ok := toInt(s); ok && expected == code {
+				return &MatchResult{Matched: true}, nil
+			}
+		}
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected status in %v, got %d", status, code),
+		}, nil
+	default:
+		return nil, fmt.Errorf("statusCode matcher requires a status class or list of codes")
+	}
+}
+
+// toInt converts a whole JSON number to an int.
+func toInt(v interface{}) (int, bool) {
+	switch n := normalizeNumber(v).(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
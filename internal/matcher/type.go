@@ -0,0 +1,59 @@
+package matcher
+
+import (
+	"fmt"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+)
+
+// TypeMatcher matches values of the same JSON type, ignoring their content.
+type TypeMatcher struct{}
+
+// NewTypeMatcher creates a new TypeMatcher.
+func NewTypeMatcher() *TypeMatcher {
+	return &TypeMatcher{}
+}
+
+// Name returns "type".
+func (m *TypeMatcher) Name() string {
+	return "type"
+}
+
+// Match checks that expected and actual have the same JSON type.
+func (m *TypeMatcher) Match(expected, actual interface{}) (*MatchResult, error) {
+	expType := jsonType(expected)
+	actType := jsonType(actual)
+	if expType != actType {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected type %s, got %s (%v)", expType, actType, actual),
+		}, nil
+	}
+	return &MatchResult{Matched: true}, nil
+}
+
+// MatchRule checks the JSON type and, for arrays, the rule's min/max length.
+func (m *TypeMatcher) MatchRule(rule contract.Matcher, expected, actual interface{}) (*MatchResult, error) {
+	result, err := m.Match(expected, actual)
+	if err != nil || !result.Matched {
+		return result, err
+	}
+
+	arr, ok := actual.([]interface{})
+	if !ok {
+		return result, nil
+	}
+	if rule.Min != nil && len(arr) < *rule.Min {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected array with at least %d elements, got %d", *rule.Min, len(arr)),
+		}, nil
+	}
+	if rule.Max != nil && len(arr) > *rule.Max {
+		return &MatchResult{
+			Matched: false,
+			Diff:    fmt.Sprintf("expected array with at most %d elements, got %d", *rule.Max, len(arr)),
+		}, nil
+	}
+	return result, nil
+}
@@ -1,21 +1,29 @@
 package mock
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
 )
 
+// formMediaType is the media type of URL-encoded form bodies.
+const formMediaType = "application/x-www-form-urlencoded"
+
 // Handler is an HTTP handler that matches requests against registered interactions.
 type Handler struct {
 	mu           sync.RWMutex
 	interactions []contract.Interaction
 	recorded     []contract.Interaction
+	comparator   *matcher.Comparator
 }
 
 // NewHandler creates a new mock handler.
@@ -23,6 +31,7 @@ func NewHandler() *Handler {
 	return &Handler{
 		interactions: make([]contract.Interaction, 0),
 		recorded:     make([]contract.Interaction, 0),
+		comparator:   matcher.NewComparator(),
 	}
 }
 
@@ -46,9 +55,11 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	body, _ := io.ReadAll(r.Body)
+
 	// Find matching interaction
 	for i := range h.interactions {
-		if h.matchRequest(r, &h.interactions[i].Request) {
+		if h.matchRequest(r, body, &h.interactions[i].Request) {
 			h.recorded = append(h.recorded, h.interactions[i])
 			h.writeResponse(w, &h.interactions[i].Response)
 			return
@@ -69,44 +80,62 @@ func (h *Handler) RecordedInteractions() []contract.Interaction {
 	return result
 }
 
-func (h *Handler) matchRequest(r *http.Request, expected *contract.Request) bool {
+func (h *Handler) matchRequest(r *http.Request, body []byte, expected *contract.Request) bool {
 	// Match method
 	if !strings.EqualFold(r.Method, expected.Method) {
 		return false
 	}
 
+	rules := expected.MatchingRules
+
 	// Match path
-	if r.URL.Path != expected.Path {
+	if !h.matches(h.comparator.ComparePath(expected.Path, r.URL.Path, rules.Path)) {
 		return false
 	}
 
 	// Match query params if specified
 	if len(expected.Query) > 0 {
-		for key, values := range expected.Query {
-			actualValues := r.URL.Query()[key]
-			if !sliceEqual(values, actualValues) {
-				return false
-			}
+		if !h.matches(h.comparator.CompareQuery(expected.Query, r.URL.Query(), rules.Query)) {
+			return false
 		}
 	}
 
 	// Match headers if specified
 	if len(expected.Headers) > 0 {
+		expectedHeaders := make(map[string]interface{}, len(expected.Headers))
+		actualHeaders := make(map[string]interface{}, len(expected.Headers))
 		for key, value := range expected.Headers {
-			actualValue := r.Header.Get(key)
-			if actualValue != fmt.Sprintf("%v", value) {
-				return false
+			expectedHeaders[key] = matcher.HeaderValue(value)
+			if values := r.Header.Values(key); len(values) > 0 {
+				actualHeaders[key] = strings.Join(values, ", ")
 			}
 		}
+		if !h.matches(h.comparator.CompareHeaders(expectedHeaders, actualHeaders, rules.Headers)) {
+			return false
+		}
+	}
+
+	// Match body if specified
+	if expected.Body != nil {
+		mediaType := contentType(expected.Headers)
+		if !h.matches(h.comparator.CompareBody(expectedBody(expected.Body, mediaType), decodeBody(body, mediaType), rules.Body)) {
+			return false
+		}
 	}
 
 	return true
 }
 
+// matches reports whether a comparison succeeded. Comparison errors, such
+// as an invalid regex in the contract, count as a mismatch.
+func (h *Handler) matches(result *matcher.MatchResult, err error) bool {
+	return err == nil && result.Matched
+}
+
 func (h *Handler) writeResponse(w http.ResponseWriter, resp *contract.Response) {
 	// Set headers
 	for key, value := range resp.Headers {
-		w.Header().Set(key, fmt.Sprintf("%v", value))
+		w.Header().Set(key, matcher.HeaderValue(value))
 	}
 
 	// Write status
@@ -123,14 +152,111 @@ func (h *Handler) writeResponse(w http.ResponseWriter, resp *contract.Response)
 	}
 }
 
-func sliceEqual(a, b []string) bool {
-	if len(a) != len(b) {
-		return false
+// contentType returns the media type of the Content-Type header in headers,
+// or "" if there is none.
+func contentType(headers map[string]interface{}) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			return matcher.MediaType(matcher.HeaderValue(value))
+		}
 	}
-	for i := range a {
-		if a[i] != b[i] {
-			return false
+	return ""
+}
+
+// decodeBody decodes a request body according to the expected media type.
+// Form bodies become an object of their fields and other non-JSON bodies
+// are kept as a string. Without a media type, bodies that are valid JSON
+// are decoded as JSON.
+func decodeBody(data []byte, mediaType string) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	switch {
+	case mediaType == formMediaType:
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return string(data)
 		}
+		return formFields(values)
+	case mediaType != "" && !matcher.IsJSONMediaType(mediaType):
+		return string(data)
 	}
-	return true
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return string(data)
+	}
+	return v
+}
+
+// expectedBody prepares an expected body for comparison with a body decoded
+// by decodeBody. Form bodies, written either as an object or as an encoded
+// string, become an object of string fields.
+func expectedBody(body interface{}, mediaType string) interface{} {
+	if mediaType != formMediaType {
+		return normalizeJSON(body)
+	}
+	switch b := body.(type) {
+	case string:
+		values, err := url.ParseQuery(b)
+		if err != nil {
+			return b
+		}
+		return formFields(values)
+	case map[string]interface{}:
+		values := url.Values{}
+		for key, value := range b {
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					values.Add(key, formatValue(item))
+				}
+				continue
+			}
+			values.Add(key, formatValue(value))
+		}
+		return formFields(values)
+	}
+	return normalizeJSON(body)
+}
+
+// formFields converts form values into an object. Fields with several
+// values become arrays.
+func formFields(values url.Values) map[string]interface{} {
+	fields := make(map[string]interface{}, len(values))
+	for key, list := range values {
+		if len(list) == 1 {
+			fields[key] = list[0]
+			continue
+		}
+		items := make([]interface{}, len(list))
+		for i, item := range list {
+			items[i] = item
+		}
+		fields[key] = items
+	}
+	return fields
+}
+
+// formatValue formats a JSON value as a form field. Numbers are written in
+// full, so 1500000 does not become 1.5e+06.
+func formatValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// normalizeJSON round-trips v through JSON so that values registered from Go
+// code (e.g. int) compare equal to decoded request bodies (float64).
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return v
+	}
+	return result
 }
@@ -0,0 +1,178 @@
+package verifier
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+// encodeRequestBody encodes a request body according to the media type of
+// the request's Content-Type header. It also returns the Content-Type to
+// send instead of the contract's one, which is only set when the encoding
+// has to choose a multipart boundary.
+//
+// Bodies in the Pact v4 form {"content": ..., "encoded": "base64"} and
+// string bodies of binary media types are base64 decoded.
+func encodeRequestBody(body interface{}, headers map[string]interface{}) ([]byte, string, error) {
+	mediaType := headerMediaType(headers)
+
+	if content, ok := encodedContent(body); ok {
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 body: %w", err)
+		}
+		return data, "", nil
+	}
+
+	switch {
+	case mediaType == "" || matcher.IsJSONMediaType(mediaType):
+		data, err := json.Marshal(body)
+		return data, "", err
+	case mediaType == "application/x-www-form-urlencoded":
+		if s, ok := body.(string); ok {
+			return []byte(s), "", nil
+		}
+		values, err := formValues(body)
+		if err != nil {
+			return nil, "", err
+		}
+		return []byte(values.Encode()), "", nil
+	case mediaType == "multipart/form-data":
+		if s, ok := body.(string); ok {
+			return []byte(s), "", nil
+		}
+		return encodeMultipart(body)
+	}
+
+	s, ok := body.(string)
+	if !ok {
+		data, err := json.Marshal(body)
+		return data, "", err
+	}
+	if !isTextMediaType(mediaType) {
+		// Binary bodies are stored base64 encoded; send anything that does
+		// not decode as is.
+		if data, err := base64.StdEncoding.DecodeString(s); err == nil {
+			return data, "", nil
+		}
+	}
+	return []byte(s), "", nil
+}
+
+// decodeExpectedBody returns an expected non-JSON response body as the
+// provider sends it. Like request bodies, Pact v4 base64 encoded bodies and
+// string bodies of binary media types are base64 decoded; bodies that do not
+// decode are returned as is.
+func decodeExpectedBody(body interface{}, mediaType string) interface{} {
+	if content, ok := encodedContent(body); ok {
+		if data, err := base64.StdEncoding.DecodeString(content); err == nil {
+			return string(data)
+		}
+		return body
+	}
+
+	s, ok := body.(string)
+	if !ok || mediaType == "" || matcher.IsJSONMediaType(mediaType) || isTextMediaType(mediaType) {
+		return body
+	}
+	if data, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return string(data)
+	}
+	return body
+}
+
+// encodedContent returns the content of a Pact v4 base64 encoded body.
+func encodedContent(body interface{}) (string, bool) {
+	m, ok := body.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	content, ok := m["content"].(string)
+	if !ok {
+		return "", false
+	}
+	encoded, _ := m["encoded"].(string)
+	return content, strings.EqualFold(encoded, "base64")
+}
+
+// formValues converts an object body into form values. Array fields become
+// repeated keys.
+func formValues(body interface{}) (url.Values, error) {
+	fields, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("form body must be an object or a string, got %T", body)
+	}
+
+	values := url.Values{}
+	for key, value := range fields {
+		if list, ok := value.([]interface{}); ok {
+			for _, item := range list {
+				values.Add(key, formatValue(item))
+			}
+			continue
+		}
+		values.Add(key, formatValue(value))
+	}
+	return values, nil
+}
+
+// formatValue formats a JSON value for use in text such as a form field, a
+// path or a header. Numbers are written in full, so 1500000 does not become
+// 1.5e+06.
+func formatValue(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// encodeMultipart encodes an object body as multipart/form-data, one field
+// per key, and returns the Content-Type carrying the generated boundary.
+func encodeMultipart(body interface{}) ([]byte, string, error) {
+	values, err := formValues(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if err := w.WriteField(key, value); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// isTextMediaType reports whether a media type holds text rather than
+// binary data.
+func isTextMediaType(mediaType string) bool {
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+xml"),
+		mediaType == "application/xml",
+		mediaType == "application/javascript",
+		mediaType == "application/graphql":
+		return true
+	}
+	return false
+}
@@ -1,132 +1,69 @@
 package verifier
 
 import (
-	"fmt"
-	"reflect"
-	"strings"
-
 	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
 )
 
 // CompareResult holds the result of a comparison.
 type CompareResult struct {
-	Match bool
-	Diff  string
+	Match      bool
+	Diff       string
+	Mismatches []matcher.Mismatch
 }
 
-// Comparer compares expected and actual values.
-type Comparer struct{}
+// Comparer compares expected and actual values. Every comparison is
+// delegated to the rule-aware matcher package, so any matcher registered
+// there is honoured during verification.
+type Comparer struct {
+	matcher *matcher.Comparator
+}
 
 // NewComparer creates a new Comparer.
 func NewComparer() *Comparer {
-	return &Comparer{}
+	return &Comparer{matcher: matcher.NewComparator()}
 }
 
-// CompareStatus compares status codes.
-func (c *Comparer) CompareStatus(expected, actual int) CompareResult {
-	if expected == actual {
-		return CompareResult{Match: true}
-	}
-	return CompareResult{
-		Match: false,
-		Diff:  fmt.Sprintf("expected status %d, got %d", expected, actual),
-	}
+// CompareStatus compares status codes using the status matching rule.
+func (c *Comparer) CompareStatus(expected, actual int, rule *contract.MatcherSet) (*CompareResult, error) {
+	return compareResult(c.matcher.CompareStatus(expected, actual, rule))
 }
 
-// CompareHeaders compares headers.
-func (c *Comparer) CompareHeaders(expected map[string]interface{}, actual map[string]string) CompareResult {
+// CompareHeaders compares headers using the header matching rules.
+func (c *Comparer) CompareHeaders(expected map[string]interface{}, actual map[string]string, rules map[string]contract.MatcherSet) (*CompareResult, error) {
 	if expected == nil {
-		return CompareResult{Match: true}
+		return &CompareResult{Match: true}, nil
 	}
 
-	var diffs []string
-	for key, expVal := range expected {
-		actVal, ok := actual[key]
-		if !ok {
-			diffs = append(diffs, fmt.Sprintf("missing header: %s", key))
-			continue
-		}
-		if fmt.Sprintf("%v", expVal) != actVal {
-			diffs = append(diffs, fmt.Sprintf("header %s: expected %v, got %s", key, expVal, actVal))
-		}
+	// Header values are strings on the wire
+	expectedValues := make(map[string]interface{}, len(expected))
+	for key, value := range expected {
+		expectedValues[key] = matcher.HeaderValue(value)
 	}
-
-	if len(diffs) > 0 {
-		return CompareResult{Match: false, Diff: strings.Join(diffs, "; ")}
+	actualValues := make(map[string]interface{}, len(actual))
+	for key, value := range actual {
+		actualValues[key] = value
 	}
-	return CompareResult{Match: true}
+	return compareResult(c.matcher.CompareHeaders(expectedValues, actualValues, rules))
 }
 
-// CompareBody compares body content.
-func (c *Comparer) CompareBody(expected, actual interface{}, rules map[string]contract.MatcherSet) (*CompareResult, error) {
-	if expected == nil {
-		return &CompareResult{Match: true}, nil
-	}
-
-	diffs := c.compareValues("$", expected, actual)
-	if len(diffs) > 0 {
-		return &CompareResult{Match: false, Diff: strings.Join(diffs, "; ")}, nil
-	}
-	return &CompareResult{Match: true}, nil
+// CompareQuery compares query parameters using the query matching rules.
+func (c *Comparer) CompareQuery(expected, actual map[string][]string, rules map[string]contract.MatcherSet) (*CompareResult, error) {
+	return compareResult(c.matcher.CompareQuery(expected, actual, rules))
 }
 
-func (c *Comparer) compareValues(path string, expected, actual interface{}) []string {
+// CompareBody compares body content using the body matching rules.
+func (c *Comparer) CompareBody(expected, actual interface{}, rules map[string]contract.MatcherSet) (*CompareResult, error) {
 	if expected == nil {
-		return nil
-	}
-
-	expVal := reflect.ValueOf(expected)
-	actVal := reflect.ValueOf(actual)
-
-	switch expVal.Kind() {
-	case reflect.Map:
-		return c.compareMaps(path, expVal, actVal)
-	case reflect.Slice:
-		return c.compareSlices(path, expVal, actVal)
-	default:
-		if !reflect.DeepEqual(expected, actual) {
-			return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
-		}
-		return nil
-	}
-}
-
-func (c *Comparer) compareMaps(path string, expected, actual reflect.Value) []string {
-	if actual.Kind() != reflect.Map {
-		return []string{fmt.Sprintf("%s: expected object, got %v", path, actual.Kind())}
-	}
-
-	var diffs []string
-	for _, key := range expected.MapKeys() {
-		keyStr := fmt.Sprintf("%v", key.Interface())
-		expElem := expected.MapIndex(key)
-		actElem := actual.MapIndex(key)
-
-		if !actElem.IsValid() {
-			diffs = append(diffs, fmt.Sprintf("%s.%s: missing field", path, keyStr))
-			continue
-		}
-
-		childDiffs := c.compareValues(path+"."+keyStr, expElem.Interface(), actElem.Interface())
-		diffs = append(diffs, childDiffs...)
+		return &CompareResult{Match: true}, nil
 	}
-	return diffs
+	return compareResult(c.matcher.CompareBody(expected, actual, rules))
 }
 
-func (c *Comparer) compareSlices(path string, expected, actual reflect.Value) []string {
-	if actual.Kind() != reflect.Slice {
-		return []string{fmt.Sprintf("%s: expected array, got %v", path, actual.Kind())}
-	}
-
-	if expected.Len() != actual.Len() {
-		return []string{fmt.Sprintf("%s: expected array length %d, got %d", path, expected.Len(), actual.Len())}
-	}
-
-	var diffs []string
-	for i := 0; i < expected.Len(); i++ {
-		childPath := fmt.Sprintf("%s[%d]", path, i)
-		childDiffs := c.compareValues(childPath, expected.Index(i).Interface(), actual.Index(i).Interface())
-		diffs = append(diffs, childDiffs...)
+// compareResult converts a matcher result into a CompareResult.
+func compareResult(result *matcher.MatchResult, err error) (*CompareResult, error) {
+	if err != nil {
+		return nil, err
 	}
-	return diffs
+	return &CompareResult{Match: result.Matched, Diff: result.Diff, Mismatches: result.Mismatches}, nil
 }
@@ -0,0 +1,47 @@
+package verifier
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// RequestFilter modifies a provider request before it is sent, e.g. to
+// replace placeholder credentials from the contract, rewrite the host or
+// sign the request. Filters may read and replace the body.
+type RequestFilter func(req *http.Request) error
+
+// customizeRequest applies Config.CustomHeaders and Config.RequestFilter to
+// req. A body replaced by the filter is buffered so that retries resend it.
+func (v *Verifier) customizeRequest(req *http.Request) error {
+	for key, value := range v.config.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+	if v.config.RequestFilter == nil {
+		return nil
+	}
+
+	if err := v.config.RequestFilter(req); err != nil {
+		return err
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	if len(body) == 0 {
+		req.ContentLength = 0
+		req.Body = http.NoBody
+		req.GetBody = func() (io.ReadCloser, error) { return http.NoBody, nil }
+		return nil
+	}
+	req.ContentLength = int64(len(body))
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package verifier
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+// providerStateGenerator is the generator type that takes its value from
+// the provider state.
+const providerStateGenerator = "ProviderState"
+
+// stateExpression matches the "${name}" placeholders of a ProviderState
+// generator expression.
+var stateExpression = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// applyStateGenerators returns a copy of req with its ProviderState
+// generators applied, substituting the values returned by provider state
+// setup into the path, query, headers and body. The contract itself is not
+// modified. Other generator types are left to the consumer side.
+func applyStateGenerators(req contract.Request, values map[string]interface{}) (contract.Request, error) {
+	gens := req.Generators
+
+	if gens.Path.Type == providerStateGenerator {
+		path, err := evaluateExpression(gens.Path.Expression, values)
+		if err != nil {
+			return req, fmt.Errorf("path: %w", err)
+		}
+		req.Path = formatValue(path)
+	}
+
+	if len(gens.Query) > 0 {
+		query := make(contract.Query, len(req.Query))
+		for key, value := range req.Query {
+			query[key] = value
+		}
+		for key, gen := range gens.Query {
+			if gen.Type != providerStateGenerator {
+				continue
+			}
+			value, err := evaluateExpression(gen.Expression, values)
+			if err != nil {
+				return req, fmt.Errorf("query %s: %w", key, err)
+			}
+			query[key] = []string{formatValue(value)}
+		}
+		req.Query = query
+	}
+
+	if len(gens.Headers) > 0 {
+		headers := make(map[string]interface{}, len(req.Headers))
+		for key, value := range req.Headers {
+			headers[key] = value
+		}
+		for key, gen := range gens.Headers {
+			if gen.Type != providerStateGenerator {
+				continue
+			}
+			value, err := evaluateExpression(gen.Expression, values)
+			if err != nil {
+				return req, fmt.Errorf("header %s: %w", key, err)
+			}
+			headers[key] = formatValue(value)
+		}
+		req.Headers = headers
+	}
+
+	for path, gen := range gens.Body {
+		if gen.Type != providerStateGenerator {
+			continue
+		}
+		tokens, err := matcher.ParsePath(path)
+		if err != nil {
+			return req, err
+		}
+		value, err := evaluateExpression(gen.Expression, values)
+		if err != nil {
+			return req, fmt.Errorf("body %s: %w", path, err)
+		}
+		req.Body = replaceAt(req.Body, tokens[1:], value)
+	}
+
+	return req, nil
+}
+
+// evaluateExpression substitutes provider state values into a generator
+// expression. An expression that is a single placeholder keeps the type of
+// its value, so numeric IDs stay numbers in JSON bodies.
+func evaluateExpression(expression string, values map[string]interface{}) (interface{}, error) {
+	if m := stateExpression.FindStringSubmatch(expression); m != nil && m[0] == expression {
+		value, ok := values[m[1]]
+		if !ok {
+			return nil, fmt.Errorf("no provider state value for %q", m[1])
+		}
+		return value, nil
+	}
+
+	var missing []string
+	result := stateExpression.ReplaceAllStringFunc(expression, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-1]
+		value, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			return placeholder
+		}
+		return formatValue(value)
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no provider state value for %q", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// replaceAt returns a copy of node with the values at the path tokens
+// replaced by value. Wildcards replace every element or field; paths that
+// do not exist in node are ignored.
+func replaceAt(node interface{}, tokens []string, value interface{}) interface{} {
+	if len(tokens) == 0 {
+		return value
+	}
+	token, rest := tokens[0], tokens[1:]
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(n))
+		for key, child := range n {
+			result[key] = child
+		}
+		for key, child := range n {
+			if token == "*" || token == key {
+				result[key] = replaceAt(child, rest, value)
+			}
+		}
+		return result
+	case []interface{}:
+		if !strings.HasPrefix(token, "[") {
+			return node
+		}
+		result := make([]interface{}, len(n))
+		copy(result, n)
+		index := token[1 : len(token)-1]
+		if index == "*" {
+			for i := range result {
+				result[i] = replaceAt(result[i], rest, value)
+			}
+			return result
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 || i >= len(result) {
+			return node
+		}
+		result[i] = replaceAt(result[i], rest, value)
+		return result
+	}
+	return node
+}
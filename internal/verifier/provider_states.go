@@ -4,15 +4,35 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
 )
 
-// ProviderStates handles provider state setup.
+// Provider state change actions.
+const (
+	ActionSetup    = "setup"
+	ActionTeardown = "teardown"
+)
+
+// StateHandler sets up or tears down a provider state in process. It
+// receives the params of the state and may return values for ProviderState
+// generators.
+type StateHandler func(params map[string]interface{}) (map[string]interface{}, error)
+
+// ProviderStates handles provider state changes. States with an in-process
+// handler are changed by calling it, and states set up in process are never
+// torn down through the setup URL. Other changes are a POST to the setup
+// URL carrying the state name, its params and the action, either as a JSON
+// body (the default) or as query parameters.
 type ProviderStates struct {
-	setupURL string
-	client   *http.Client
+	setupURL         string
+	asQuery          bool
+	setupHandlers    map[string]StateHandler
+	teardownHandlers map[string]StateHandler
+	client           *http.Client
 }
 
 // NewProviderStates creates a new ProviderStates.
@@ -23,14 +43,125 @@ func NewProviderStates(setupURL string) *ProviderStates {
 	}
 }
 
+// SetAsQuery sets whether state changes are sent as query parameters
+// instead of a JSON body.
+func (ps *ProviderStates) SetAsQuery(asQuery bool) {
+	ps.asQuery = asQuery
+}
+
+// SetHandlers sets the in-process setup and teardown handlers, keyed by
+// state name.
+func (ps *ProviderStates) SetHandlers(setup, teardown map[string]StateHandler) {
+	ps.setupHandlers = setup
+	ps.teardownHandlers = teardown
+}
+
 // Setup sets up a single provider state.
 func (ps *ProviderStates) Setup(state string, params map[string]interface{}) error {
+	_, err := ps.change(ActionSetup, state, params)
+	return err
+}
+
+// SetupWithValues sets up a single provider state and returns the values
+// the setup URL responded with as a JSON object, such as the IDs of the
+// records it created. The values are nil if the response is not an object.
+func (ps *ProviderStates) SetupWithValues(state string, params map[string]interface{}) (map[string]interface{}, error) {
+	return ps.change(ActionSetup, state, params)
+}
+
+// Teardown tears down a single provider state.
+func (ps *ProviderStates) Teardown(state string, params map[string]interface{}) error {
+	_, err := ps.change(ActionTeardown, state, params)
+	return err
+}
+
+// SetupMultiple sets up multiple provider states (v3).
+func (ps *ProviderStates) SetupMultiple(states []contract.ProviderState) error {
+	for _, state := range states {
+		if err := ps.Setup(state.Name, state.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TeardownMultiple tears down multiple provider states (v3) in reverse
+// order. Every state is torn down even if an earlier one fails; the first
+// error is returned.
+func (ps *ProviderStates) TeardownMultiple(states []contract.ProviderState) error {
+	var firstErr error
+	for i := len(states) - 1; i >= 0; i-- {
+		if err := ps.Teardown(states[i].Name, states[i].Params); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (ps *ProviderStates) change(action, state string, params map[string]interface{}) (map[string]interface{}, error) {
+	handlers := ps.setupHandlers
+	if action == ActionTeardown {
+		handlers = ps.teardownHandlers
+	}
+	if handler, ok := handlers[state]; ok {
+		values, err := handler(params)
+		if err != nil {
+			return nil, fmt.Errorf("provider state %s handler failed: %w", action, err)
+		}
+		return values, nil
+	}
+	if _, ok := ps.setupHandlers[state]; ok && action == ActionTeardown {
+		// The state was set up in process, so the setup URL knows nothing
+		// about it and there is nothing to tear down
+		return nil, nil
+	}
+
 	if ps.setupURL == "" {
-		return nil
+		// States are optional without a setup URL, unless handlers are
+		// registered and one is missing
+		if action == ActionSetup && len(ps.setupHandlers) > 0 {
+			return nil, fmt.Errorf("no handler for provider state %q", state)
+		}
+		return nil, nil
 	}
 
+	var req *http.Request
+	var err error
+	if ps.asQuery {
+		req, err = ps.queryRequest(action, state, params)
+	} else {
+		req, err = ps.bodyRequest(action, state, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ps.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call provider states %s: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("provider states %s failed with status %d", action, resp.StatusCode)
+	}
+
+	// Setup URLs that do not return values may respond with anything
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider states %s response: %w", action, err)
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil, nil
+	}
+	return values, nil
+}
+
+func (ps *ProviderStates) bodyRequest(action, state string, params map[string]interface{}) (*http.Request, error) {
 	body := map[string]interface{}{
-		"state": state,
+		"state":  state,
+		"action": action,
 	}
 	if params != nil {
 		body["params"] = params
@@ -38,28 +169,44 @@ func (ps *ProviderStates) Setup(state string, params map[string]interface{}) err
 
 	data, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("failed to marshal provider state: %w", err)
+		return nil, fmt.Errorf("failed to marshal provider state: %w", err)
 	}
 
-	resp, err := ps.client.Post(ps.setupURL, "application/json", bytes.NewReader(data))
+	req, err := http.NewRequest(http.MethodPost, ps.setupURL, bytes.NewReader(data))
 	if err != nil {
-		return fmt.Errorf("failed to call provider states setup: %w", err)
+		return nil, fmt.Errorf("failed to create provider states request: %w", err)
 	}
-	defer resp.Body.Close()
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
 
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("provider states setup failed with status %d", resp.StatusCode)
+// queryRequest sends the state, the action and every param as query
+// parameters. Params that are not strings are sent in their JSON form.
+func (ps *ProviderStates) queryRequest(action, state string, params map[string]interface{}) (*http.Request, error) {
+	u, err := url.Parse(ps.setupURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider states setup URL: %w", err)
 	}
 
-	return nil
-}
-
-// SetupMultiple sets up multiple provider states (v3).
-func (ps *ProviderStates) SetupMultiple(states []contract.ProviderState) error {
-	for _, state := range states {
-		if err := ps.Setup(state.Name, state.Params); err != nil {
-			return err
+	query := u.Query()
+	for key, value := range params {
+		if s, ok := value.(string); ok {
+			query.Set(key, s)
+			continue
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal provider state param %q: %w", key, err)
 		}
+		query.Set(key, string(data))
 	}
-	return nil
+	query.Set("state", state)
+	query.Set("action", action)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider states request: %w", err)
+	}
+	return req, nil
 }
@@ -46,6 +46,29 @@ func (r *Reporter) Report(result *VerificationResult) {
 	fmt.Fprintf(r.w, "\nSummary: %d passed, %d failed (total: %d)\n", passed, failed, len(result.Interactions))
 }
 
+// ReportTotal writes the totals over the results of several contracts.
+func (r *Reporter) ReportTotal(results []*VerificationResult) {
+	passed := 0
+	failed := 0
+	failedContracts := 0
+
+	for _, result := range results {
+		if !result.Success {
+			failedContracts++
+		}
+		for i := range result.Interactions {
+			if result.Interactions[i].Success {
+				passed++
+			} else {
+				failed++
+			}
+		}
+	}
+
+	fmt.Fprintf(r.w, "\nTotal: %d contracts (%d failed), %d interactions passed, %d failed\n",
+		len(results), failedContracts, passed, failed)
+}
+
 func (r *Reporter) printRequestInfo(ir *InteractionResult) {
 	if ir.RequestMethod != "" {
 		fmt.Fprintf(r.w, "    Request: %s %s\n", ir.RequestMethod, ir.RequestPath)
@@ -60,7 +83,16 @@ func (r *Reporter) printFailureDetails(ir *InteractionResult) {
 	if ir.Error != "" {
 		fmt.Fprintf(r.w, "    Error: %s\n", ir.Error)
 	}
-	if ir.Diff != "" {
+	if len(ir.Mismatches) > 0 {
+		fmt.Fprintf(r.w, "    Diff:\n")
+		for _, m := range ir.Mismatches {
+			if m.Matcher != "" {
+				fmt.Fprintf(r.w, "      %s (%s): %s\n", m.Location(), m.Matcher, m.Message)
+			} else {
+				fmt.Fprintf(r.w, "      %s: %s\n", m.Location(), m.Message)
+			}
+		}
+	} else if ir.Diff != "" {
 		fmt.Fprintf(r.w, "    Diff: %s\n", ir.Diff)
 	}
 
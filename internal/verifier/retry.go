@@ -0,0 +1,96 @@
+package verifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultWaitTimeout bounds waitForProvider when Config.WaitTimeout is zero.
+const defaultWaitTimeout = 30 * time.Second
+
+// waitInterval is the delay between readiness polls.
+const waitInterval = 200 * time.Millisecond
+
+// do sends req, retrying up to Config.Retries times when the connection to
+// the provider cannot be established, e.g. because it is refused while the
+// provider is starting. The delay starts at Config.RetryBackoff and doubles
+// after each attempt. Timeouts and responses, whatever their status, are
+// never retried, as the provider may already have handled the request.
+func (v *Verifier) do(req *http.Request) (*http.Response, error) {
+	backoff := v.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := v.client.Do(req)
+		if err == nil || attempt >= v.config.Retries || !isDialError(err) {
+			return resp, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// isDialError reports whether err is a failure to connect that did not time
+// out, so the request never reached the provider.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial" && !opErr.Timeout()
+}
+
+// waitForProvider polls Config.WaitURL until it answers with a 2xx status or
+// Config.WaitTimeout elapses. Each poll is bounded by the remaining wait
+// time, so a provider that accepts connections but never answers cannot
+// stall verification.
+func (v *Verifier) waitForProvider() error {
+	timeout := v.config.WaitTimeout
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+	deadline := time.Now().Add(timeout)
+
+	var lastErr error
+	for {
+		lastErr = v.pollProvider(deadline)
+		if lastErr == nil {
+			return nil
+		}
+
+		if time.Now().Add(waitInterval).After(deadline) {
+			return fmt.Errorf("provider not ready at %s after %s: %v", v.config.WaitURL, timeout, lastErr)
+		}
+		time.Sleep(waitInterval)
+	}
+}
+
+// pollProvider requests Config.WaitURL once, giving up at deadline, and
+// returns an error unless it answers with a 2xx status.
+func (v *Verifier) pollProvider(deadline time.Time) error {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.WaitURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
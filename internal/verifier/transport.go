@@ -0,0 +1,44 @@
+package verifier
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+)
+
+// handlerBaseURL is the base URL of requests served by an in-memory
+// handler. Handlers see its host as the request host.
+const handlerBaseURL = "http://provider"
+
+// handlerTransport is an http.RoundTripper that serves requests with an
+// http.Handler in memory, without a network listener.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip serves req with the handler and returns the recorded response.
+// A panicking handler is reported as an error, as an HTTP server would
+// drop the connection.
+func (t *handlerTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	// Turn the client request into the request a server would see
+	serverReq := req.Clone(req.Context())
+	serverReq.RequestURI = req.URL.RequestURI()
+	serverReq.RemoteAddr = "192.0.2.1:1234"
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			resp = nil
+			err = fmt.Errorf("provider handler panicked: %v", r)
+		}
+	}()
+
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, serverReq)
+
+	resp = rec.Result()
+	resp.Request = req
+	return resp, nil
+}
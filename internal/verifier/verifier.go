@@ -8,14 +8,52 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
 )
 
 // Config holds verifier configuration.
 type Config struct {
-	ProviderBaseURL        string
+	ProviderBaseURL string
+	// Handler, if set, serves provider requests in memory instead of
+	// ProviderBaseURL, e.g. the provider's router inside go test.
+	Handler                http.Handler
 	ProviderStatesSetupURL string
+	// ProviderStatesAsQuery sends state changes as query parameters instead
+	// of a JSON body.
+	ProviderStatesAsQuery bool
+	// StateHandlers set up provider states in process, keyed by state name.
+	// They take precedence over ProviderStatesSetupURL.
+	StateHandlers map[string]StateHandler
+	// StateTeardownHandlers tear down provider states in process after each
+	// interaction.
+	StateTeardownHandlers map[string]StateHandler
+	// Parallel is the number of interactions verified concurrently.
+	// Interactions that share a provider state are always verified one
+	// after the other. Values below 2 verify sequentially.
+	Parallel int
+	// RequestTimeout limits each request to the provider and the provider
+	// states setup URL. Zero means no timeout.
+	RequestTimeout time.Duration
+	// Retries is the number of times a provider request is retried when the
+	// connection to the provider fails, e.g. is refused. Timeouts are not
+	// retried. The first retry waits RetryBackoff and each further retry
+	// waits twice as long as the previous one.
+	Retries      int
+	RetryBackoff time.Duration
+	// WaitURL, if set, is polled before the first interaction until it
+	// answers with a 2xx status, for at most WaitTimeout (30s if zero).
+	WaitURL     string
+	WaitTimeout time.Duration
+	// CustomHeaders are set on every provider request, replacing headers of
+	// the same name from the contract.
+	CustomHeaders map[string]string
+	// RequestFilter, if set, is called with every provider request after
+	// CustomHeaders are applied.
+	RequestFilter RequestFilter
 }
 
 // VerificationResult holds the result of a verification.
@@ -29,6 +67,7 @@ type InteractionResult struct {
 	Description    string
 	Success        bool
 	Diff           string
+	Mismatches     []matcher.Mismatch
 	Error          string
 	RequestMethod  string
 	RequestPath    string
@@ -55,11 +94,20 @@ type Verifier struct {
 
 // New creates a new Verifier.
 func New(config Config) *Verifier {
+	providerStates := NewProviderStates(config.ProviderStatesSetupURL)
+	providerStates.SetAsQuery(config.ProviderStatesAsQuery)
+	providerStates.SetHandlers(config.StateHandlers, config.StateTeardownHandlers)
+	providerStates.client.Timeout = config.RequestTimeout
+	client := &http.Client{Timeout: config.RequestTimeout}
+	if config.Handler != nil {
+		client.Transport = &handlerTransport{handler: config.Handler}
+		config.ProviderBaseURL = handlerBaseURL
+	}
 	return &Verifier{
 		config:         config,
-		client:         &http.Client{},
+		client:         client,
 		comparer:       NewComparer(),
-		providerStates: NewProviderStates(config.ProviderStatesSetupURL),
+		providerStates: providerStates,
 	}
 }
 
@@ -67,13 +115,37 @@ func New(config Config) *Verifier {
 func (v *Verifier) Verify(c *contract.Contract) (*VerificationResult, error) {
 	result := &VerificationResult{
 		Success:      true,
-		Interactions: make([]InteractionResult, 0, len(c.Interactions)),
+		Interactions: make([]InteractionResult, len(c.Interactions)),
+	}
+
+	if v.config.WaitURL != "" {
+		if err := v.waitForProvider(); err != nil {
+			return nil, err
+		}
+	}
+
+	groups := stateGroups(c.Interactions)
+	work := make(chan []int)
+	var wg sync.WaitGroup
+	for range min(max(v.config.Parallel, 1), len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range work {
+				for _, i := range group {
+					result.Interactions[i] = v.verifyInteraction(&c.Interactions[i])
+				}
+			}
+		}()
+	}
+	for _, group := range groups {
+		work <- group
 	}
+	close(work)
+	wg.Wait()
 
-	for i := range c.Interactions {
-		ir := v.verifyInteraction(&c.Interactions[i])
-		result.Interactions = append(result.Interactions, ir)
-		if !ir.Success {
+	for i := range result.Interactions {
+		if !result.Interactions[i].Success {
 			result.Success = false
 		}
 	}
@@ -81,8 +153,53 @@ func (v *Verifier) Verify(c *contract.Contract) (*VerificationResult, error) {
 	return result, nil
 }
 
-func (v *Verifier) verifyInteraction(interaction *contract.Interaction) InteractionResult {
-	ir := InteractionResult{
+// stateGroups splits interactions into groups that can be verified
+// concurrently: interactions sharing a provider state, directly or through
+// other interactions, end up in the same group. Groups and the indices in
+// them keep the order of the contract.
+func stateGroups(interactions []contract.Interaction) [][]int {
+	// Union-find over interaction indices
+	parent := make([]int, len(interactions))
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	owner := make(map[string]int)
+	for i := range interactions {
+		parent[i] = i
+		for _, state := range interactionStates(&interactions[i]) {
+			j, ok := owner[state.Name]
+			if !ok {
+				owner[state.Name] = i
+				continue
+			}
+			if ri, rj := find(i), find(j); ri != rj {
+				parent[max(ri, rj)] = min(ri, rj)
+			}
+		}
+	}
+
+	var groups [][]int
+	groupOf := make(map[int]int)
+	for i := range interactions {
+		root := find(i)
+		g, ok := groupOf[root]
+		if !ok {
+			g = len(groups)
+			groupOf[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+func (v *Verifier) verifyInteraction(interaction *contract.Interaction) (ir InteractionResult) {
+	ir = InteractionResult{
 		Description:     interaction.Description,
 		RequestMethod:   interaction.Request.Method,
 		RequestPath:     interaction.Request.Path,
@@ -94,74 +211,127 @@ func (v *Verifier) verifyInteraction(interaction *contract.Interaction) Interact
 		RequestBody:     interaction.Request.Body,
 	}
 
-	// Setup provider states
-	if interaction.ProviderState != "" {
-		if err := v.providerStates.Setup(interaction.ProviderState, nil); err != nil {
-			ir.Error = fmt.Sprintf("failed to setup provider state: %v", err)
-			return ir
+	// Set up provider states, and tear down the ones that were set up once
+	// the interaction is done, whatever its outcome
+	states := interactionStates(interaction)
+	values := make(map[string]interface{})
+	for i := range states {
+		stateValues, err := v.providerStates.SetupWithValues(states[i].Name, states[i].Params)
+		if err != nil {
+			ir.Error = fmt.Sprintf("failed to setup provider state %q: %v", states[i].Name, err)
+			states = states[:i]
+			break
+		}
+		// Values returned by the setup take precedence over the params
+		for key, value := range states[i].Params {
+			values[key] = value
+		}
+		for key, value := range stateValues {
+			values[key] = value
 		}
 	}
-	if len(interaction.ProviderStates) > 0 {
-		if err := v.providerStates.SetupMultiple(interaction.ProviderStates); err != nil {
-			ir.Error = fmt.Sprintf("failed to setup provider states: %v", err)
-			return ir
+	defer func() {
+		if err := v.providerStates.TeardownMultiple(states); err != nil && ir.Error == "" {
+			ir.Success = false
+			ir.Error = fmt.Sprintf("failed to teardown provider states: %v", err)
 		}
+	}()
+	if ir.Error != "" {
+		return ir
+	}
+
+	request, err := applyStateGenerators(interaction.Request, values)
+	if err != nil {
+		ir.Error = fmt.Sprintf("failed to apply provider state generators: %v", err)
+		return ir
 	}
+	ir.RequestPath = request.Path
+	ir.RequestHeaders = request.Headers
+	ir.RequestBody = request.Body
 
+	generated := *interaction
+	generated.Request = request
+	v.exchange(&generated, &ir)
+	return ir
+}
+
+// exchange sends the interaction's request to the provider and compares the
+// response with the expected one.
+func (v *Verifier) exchange(interaction *contract.Interaction, ir *InteractionResult) {
 	// Make request to provider
 	url := v.config.ProviderBaseURL + interaction.Request.Path
+	if query := interaction.Request.Query.Encode(); query != "" {
+		url += "?" + query
+	}
 
 	// Prepare request body if present
 	var bodyReader io.Reader = http.NoBody
+	var contentType string
 	if interaction.Request.Body != nil {
-		bodyBytes, err := json.Marshal(interaction.Request.Body)
+		bodyBytes, encodedType, err := encodeRequestBody(interaction.Request.Body, interaction.Request.Headers)
 		if err != nil {
-			ir.Error = fmt.Sprintf("failed to marshal request body: %v", err)
-			return ir
+			ir.Error = fmt.Sprintf("failed to encode request body: %v", err)
+			return
 		}
 		bodyReader = bytes.NewReader(bodyBytes)
+		contentType = encodedType
 	}
 
 	req, err := http.NewRequest(interaction.Request.Method, url, bodyReader)
 	if err != nil {
 		ir.Error = fmt.Sprintf("failed to create request: %v", err)
-		return ir
+		return
 	}
 
 	// Add headers
 	for key, value := range interaction.Request.Headers {
-		req.Header.Set(key, fmt.Sprintf("%v", value))
+		req.Header.Set(key, matcher.HeaderValue(value))
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if err := v.customizeRequest(req); err != nil {
+		ir.Error = fmt.Sprintf("request filter failed: %v", err)
+		return
 	}
 
-	resp, err := v.client.Do(req)
+	resp, err := v.do(req)
 	if err != nil {
 		ir.Error = fmt.Sprintf("connection error: %v", err)
-		return ir
+		return
 	}
 	defer resp.Body.Close()
 
 	ir.ResponseStatus = resp.StatusCode
 
 	// Compare response
-	var diffs []string
+	var mismatches []matcher.Mismatch
 
 	// Compare status
-	statusResult := v.comparer.CompareStatus(interaction.Response.Status, resp.StatusCode)
+	statusResult, err := v.comparer.CompareStatus(interaction.Response.Status, resp.StatusCode, interaction.Response.MatchingRules.Status)
+	if err != nil {
+		ir.Error = fmt.Sprintf("failed to compare status: %v", err)
+		return
+	}
 	if !statusResult.Match {
-		diffs = append(diffs, statusResult.Diff)
+		mismatches = append(mismatches, statusResult.Mismatches...)
 	}
 
 	// Compare headers
 	actualHeaders := make(map[string]string)
-	for key := range resp.Header {
-		actualHeaders[key] = resp.Header.Get(key)
+	for key, values := range resp.Header {
+		actualHeaders[key] = strings.Join(values, ", ")
 	}
 	ir.ActualHeaders = actualHeaders
 
 	if interaction.Response.Headers != nil {
-		headerResult := v.comparer.CompareHeaders(interaction.Response.Headers, actualHeaders)
+		headerResult, err := v.comparer.CompareHeaders(interaction.Response.Headers, actualHeaders, interaction.Response.MatchingRules.Headers)
+		if err != nil {
+			ir.Error = fmt.Sprintf("failed to compare headers: %v", err)
+			return
+		}
 		if !headerResult.Match {
-			diffs = append(diffs, headerResult.Diff)
+			mismatches = append(mismatches, headerResult.Mismatches...)
 		}
 	}
 
@@ -169,35 +339,78 @@ func (v *Verifier) verifyInteraction(interaction *contract.Interaction) Interact
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		ir.Error = fmt.Sprintf("failed to read response body: %v", err)
-		return ir
+		return
 	}
 	ir.ActualBodyRaw = string(body)
 
+	var actualBody interface{}
 	if len(body) > 0 {
-		var actualBody interface{}
-		if err := json.Unmarshal(body, &actualBody); err != nil {
-			ir.Error = fmt.Sprintf("failed to parse response body: %v", err)
-			return ir
+		if isJSONBody(body, headerMediaType(interaction.Response.Headers)) {
+			// Keep the original number text so integer and decimal matchers can
+			// tell 1 from 1.0
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			if err := decoder.Decode(&actualBody); err != nil {
+				ir.Error = fmt.Sprintf("failed to parse response body: %v", err)
+				return
+			}
+			ir.ActualBody = actualBody
+		} else {
+			// Plain text, HTML and binary bodies are compared as strings
+			actualBody = string(body)
 		}
-		ir.ActualBody = actualBody
 	}
 
 	if interaction.Response.Body != nil {
-		bodyResult, err := v.comparer.CompareBody(interaction.Response.Body, ir.ActualBody, interaction.Response.MatchingRules.Body)
+		expectedBody := interaction.Response.Body
+		if _, ok := actualBody.(string); ok {
+			expectedBody = decodeExpectedBody(expectedBody, headerMediaType(interaction.Response.Headers))
+		}
+		bodyResult, err := v.comparer.CompareBody(expectedBody, actualBody, interaction.Response.MatchingRules.Body)
 		if err != nil {
 			ir.Error = fmt.Sprintf("failed to compare body: %v", err)
-			return ir
+			return
 		}
 		if !bodyResult.Match {
-			diffs = append(diffs, bodyResult.Diff)
+			mismatches = append(mismatches, bodyResult.Mismatches...)
 		}
 	}
 
-	if len(diffs) > 0 {
-		ir.Diff = strings.Join(diffs, "; ")
+	if len(mismatches) > 0 {
+		ir.Mismatches = mismatches
+		ir.Diff = matcher.JoinMismatches(mismatches)
 	} else {
 		ir.Success = true
 	}
 
-	return ir
+}
+
+// interactionStates returns the provider states of an interaction: the v2
+// state followed by the v3 states.
+func interactionStates(interaction *contract.Interaction) []contract.ProviderState {
+	var states []contract.ProviderState
+	if interaction.ProviderState != "" {
+		states = append(states, contract.ProviderState{Name: interaction.ProviderState})
+	}
+	return append(states, interaction.ProviderStates...)
+}
+
+// headerMediaType returns the media type of the Content-Type in contract
+// headers, or "" if there is none.
+func headerMediaType(headers map[string]interface{}) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, "Content-Type") {
+			return matcher.MediaType(matcher.HeaderValue(value))
+		}
+	}
+	return ""
+}
+
+// isJSONBody reports whether a response body should be parsed as JSON.
+// Bodies without a known media type are parsed when they are valid JSON.
+func isJSONBody(body []byte, contentType string) bool {
+	if contentType == "" {
+		return json.Valid(body)
+	}
+	return matcher.IsJSONMediaType(contentType)
 }
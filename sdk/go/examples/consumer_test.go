@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/jt-chihara/yakusoku/sdk/go/yakusoku"
@@ -171,7 +172,7 @@ func TestCreateUser(t *testing.T) {
 		resp, err := http.Post(
 			pact.ServerURL()+"/users",
 			"application/json",
-			nil, // In real code, you'd send the body
+			strings.NewReader(`{"name":"Jane Doe","email":"jane@example.com"}`),
 		)
 		if err != nil {
 			return err
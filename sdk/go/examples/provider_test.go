@@ -10,6 +10,7 @@ import (
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
 	"github.com/jt-chihara/yakusoku/internal/verifier"
+	"github.com/jt-chihara/yakusoku/sdk/go/yakusoku"
 )
 
 // This file demonstrates how to verify a provider against consumer contracts.
@@ -62,9 +63,6 @@ func TestProviderVerification(t *testing.T) {
 				"id":   1,
 				"name": "John Doe",
 			})
-		case "/provider-states":
-			// Handle provider state setup
-			w.WriteHeader(http.StatusOK)
 		default:
 			http.NotFound(w, r)
 		}
@@ -109,56 +107,72 @@ func TestProviderVerification(t *testing.T) {
 	}
 }
 
-// TestProviderWithStates demonstrates verification with provider states.
+// TestProviderHandlerVerification demonstrates verifying a provider's
+// http.Handler in memory from go test, without starting a server.
+func TestProviderHandlerVerification(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":   1,
+			"name": "John Doe",
+		})
+	})
+
+	err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{
+		PactFiles: []string{createSampleContract(t, t.TempDir())},
+		Handler:   mux,
+		StateHandlers: map[string]yakusoku.StateHandler{
+			"user 1 exists": func(params map[string]interface{}) (map[string]interface{}, error) {
+				return nil, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestProviderWithStates demonstrates verification with provider states
+// handled in process, without a setup endpoint on the provider.
 func TestProviderWithStates(t *testing.T) {
-	var setupCalled bool
+	users := map[string]string{}
 
-	// Provider with state setup endpoint
 	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		switch r.URL.Path {
-		case "/provider-states":
-			setupCalled = true
-			var state struct {
-				State string `json:"state"`
-			}
-			json.NewDecoder(r.Body).Decode(&state)
-			t.Logf("Provider state setup called: %s", state.State)
-			w.WriteHeader(http.StatusOK)
-		case "/users/1":
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"id":   1,
-				"name": "John Doe",
-			})
-		default:
+		name, ok := users[r.URL.Path]
+		if !ok {
 			http.NotFound(w, r)
+			return
 		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":   1,
+			"name": name,
+		})
 	}))
 	defer provider.Close()
 
-	// Create contract
-	tmpDir := t.TempDir()
-	contractPath := createSampleContract(t, tmpDir)
-
-	parser := contract.NewParser()
-	c, _ := parser.ParseFile(contractPath)
-
-	// Verify with provider states URL
-	v := verifier.New(verifier.Config{
-		ProviderBaseURL:        provider.URL,
-		ProviderStatesSetupURL: provider.URL + "/provider-states",
+	// Verify with state handlers that seed and clean up the provider's data
+	err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{
+		PactFiles:       []string{createSampleContract(t, t.TempDir())},
+		ProviderBaseURL: provider.URL,
+		StateHandlers: map[string]yakusoku.StateHandler{
+			"user 1 exists": func(params map[string]interface{}) (map[string]interface{}, error) {
+				users["/users/1"] = "John Doe"
+				return nil, nil
+			},
+		},
+		StateTeardownHandlers: map[string]yakusoku.StateHandler{
+			"user 1 exists": func(params map[string]interface{}) (map[string]interface{}, error) {
+				delete(users, "/users/1")
+				return nil, nil
+			},
+		},
 	})
-
-	result, err := v.Verify(c)
 	if err != nil {
-		t.Fatalf("verification failed: %v", err)
+		t.Fatal(err)
 	}
-
-	if !setupCalled {
-		t.Error("expected provider state setup to be called")
-	}
-
-	if !result.Success {
-		t.Error("expected verification to succeed")
+	if len(users) != 0 {
+		t.Error("expected provider state teardown to remove the user")
 	}
 }
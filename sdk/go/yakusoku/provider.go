@@ -0,0 +1,102 @@
+package yakusoku
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/verifier"
+)
+
+// StateHandler sets up or tears down a provider state in process. It
+// receives the params of the state and may return values for ProviderState
+// generators, such as the ID of a record it created.
+type StateHandler func(params map[string]interface{}) (map[string]interface{}, error)
+
+// ProviderConfig holds configuration for verifying a provider.
+type ProviderConfig struct {
+	// PactFiles are the contract files to verify.
+	PactFiles []string
+	// Handler is the provider's http.Handler, e.g. its router. Requests are
+	// served in memory, without starting a server.
+	Handler http.Handler
+	// ProviderBaseURL is the URL of a running provider, used when Handler
+	// is nil.
+	ProviderBaseURL string
+	// StateHandlers set up provider states by name before each interaction,
+	// and StateTeardownHandlers tear them down afterwards.
+	StateHandlers         map[string]StateHandler
+	StateTeardownHandlers map[string]StateHandler
+	// ProviderStatesSetupURL handles the states without a StateHandlers
+	// entry.
+	ProviderStatesSetupURL string
+}
+
+// VerifyProvider verifies the provider against every contract in
+// config.PactFiles. It returns an error describing each failed interaction,
+// so it can be called from a go test:
+//
+//	err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{
+//		PactFiles: []string{"./pacts/orderservice-userservice.json"},
+//		Handler:   router,
+//	})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+func VerifyProvider(config ProviderConfig) error {
+	if len(config.PactFiles) == 0 {
+		return errors.New("no pact files to verify")
+	}
+	if config.Handler == nil && config.ProviderBaseURL == "" {
+		return errors.New("either Handler or ProviderBaseURL is required")
+	}
+
+	v := verifier.New(verifier.Config{
+		ProviderBaseURL:        config.ProviderBaseURL,
+		Handler:                config.Handler,
+		ProviderStatesSetupURL: config.ProviderStatesSetupURL,
+		StateHandlers:          toVerifierHandlers(config.StateHandlers),
+		StateTeardownHandlers:  toVerifierHandlers(config.StateTeardownHandlers),
+	})
+
+	parser := contract.NewParser()
+	var failures []string
+	for _, path := range config.PactFiles {
+		c, err := parser.ParseFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		result, err := v.Verify(c)
+		if err != nil {
+			return fmt.Errorf("failed to verify %s: %w", path, err)
+		}
+		for _, ir := range result.Interactions {
+			if ir.Success {
+				continue
+			}
+			reason := ir.Error
+			if reason == "" {
+				reason = ir.Diff
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s: %s", c.Consumer.Name, ir.Description, reason))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("provider verification failed:\n%s", strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+func toVerifierHandlers(handlers map[string]StateHandler) map[string]verifier.StateHandler {
+	if handlers == nil {
+		return nil
+	}
+	result := make(map[string]verifier.StateHandler, len(handlers))
+	for state, handler := range handlers {
+		result[state] = verifier.StateHandler(handler)
+	}
+	return result
+}
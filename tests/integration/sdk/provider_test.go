@@ -0,0 +1,104 @@
+package sdk_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/sdk/go/yakusoku"
+)
+
+func TestVerifyProvider(t *testing.T) {
+	// writePact records a contract for GET /users/1 in the given state
+	writePact := func(t *testing.T) string {
+		t.Helper()
+		tmpDir := t.TempDir()
+		pact := yakusoku.NewPact(yakusoku.Config{
+			Consumer: "OrderService",
+			Provider: "UserService",
+			PactDir:  tmpDir,
+		})
+		defer pact.Teardown()
+
+		pact.
+			Given("user 1 exists").
+			UponReceiving("a request for user 1").
+			WithRequest(yakusoku.Request{Method: "GET", Path: "/users/1"}).
+			WillRespondWith(yakusoku.Response{
+				Status:  200,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    map[string]interface{}{"id": 1, "name": "John Doe"},
+			})
+
+		require.NoError(t, pact.Verify(func() error {
+			resp, err := http.Get(pact.ServerURL() + "/users/1")
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}))
+		return filepath.Join(tmpDir, "orderservice-userservice.json")
+	}
+
+	newRouter := func(users map[string]string) http.Handler {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			name, ok := users[r.PathValue("id")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "name": name})
+		})
+		return mux
+	}
+
+	t.Run("verifies a handler with in-process provider states", func(t *testing.T) {
+		pactFile := writePact(t)
+		users := map[string]string{}
+
+		err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{
+			PactFiles: []string{pactFile},
+			Handler:   newRouter(users),
+			StateHandlers: map[string]yakusoku.StateHandler{
+				"user 1 exists": func(map[string]interface{}) (map[string]interface{}, error) {
+					users["1"] = "John Doe"
+					return nil, nil
+				},
+			},
+			StateTeardownHandlers: map[string]yakusoku.StateHandler{
+				"user 1 exists": func(map[string]interface{}) (map[string]interface{}, error) {
+					delete(users, "1")
+					return nil, nil
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("returns an error describing failed interactions", func(t *testing.T) {
+		pactFile := writePact(t)
+
+		err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{
+			PactFiles: []string{pactFile},
+			Handler:   newRouter(map[string]string{}),
+			StateHandlers: map[string]yakusoku.StateHandler{
+				"user 1 exists": func(map[string]interface{}) (map[string]interface{}, error) { return nil, nil },
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OrderService: a request for user 1")
+		assert.Contains(t, err.Error(), "expected 200, got 404")
+	})
+
+	t.Run("requires a handler or base URL", func(t *testing.T) {
+		err := yakusoku.VerifyProvider(yakusoku.ProviderConfig{PactFiles: []string{"pact.json"}})
+		require.Error(t, err)
+	})
+}
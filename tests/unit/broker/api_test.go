@@ -307,3 +307,96 @@ func TestAPI_DeleteContract_Errors(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
 	})
 }
+
+func TestAPI_Pacticipants(t *testing.T) {
+	send := func(t *testing.T, method, url string, body string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("records branches, tags, deployments and releases", func(t *testing.T) {
+		api := broker.NewAPI(broker.NewMemoryStorage())
+		server := httptest.NewServer(api.Handler())
+		defer server.Close()
+
+		base := server.URL + "/pacticipants/Consumer"
+		assert.Equal(t, http.StatusCreated, send(t, http.MethodPut, base+"/branches/feature%2Flogin/versions/1.0.0", "").StatusCode)
+		assert.Equal(t, http.StatusCreated, send(t, http.MethodPut, base+"/versions/1.0.0/tags/prod", "").StatusCode)
+		assert.Equal(t, http.StatusCreated, send(t, http.MethodPost, base+"/versions/1.0.0/deployments/production", "").StatusCode)
+		assert.Equal(t, http.StatusCreated, send(t, http.MethodPost, base+"/versions/1.0.0/releases/app-store", "").StatusCode)
+		assert.Equal(t, http.StatusOK, send(t, http.MethodPatch, base, `{"mainBranch": "develop"}`).StatusCode)
+
+		resp := send(t, http.MethodGet, base, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var p broker.Pacticipant
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+		assert.Equal(t, "develop", p.MainBranch)
+		require.Len(t, p.Versions, 1)
+		assert.Equal(t, broker.PacticipantVersion{
+			Number:     "1.0.0",
+			Branches:   []string{"feature/login"},
+			Tags:       []string{"prod"},
+			DeployedTo: []string{"production"},
+			ReleasedTo: []string{"app-store"},
+		}, p.Versions[0])
+	})
+
+	t.Run("returns 404 for unknown pacticipant", func(t *testing.T) {
+		api := broker.NewAPI(broker.NewMemoryStorage())
+		server := httptest.NewServer(api.Handler())
+		defer server.Close()
+
+		assert.Equal(t, http.StatusNotFound, send(t, http.MethodGet, server.URL+"/pacticipants/Unknown", "").StatusCode)
+	})
+}
+
+func TestAPI_PactsForVerification(t *testing.T) {
+	storage := broker.NewMemoryStorage()
+	storage.SaveContract(createTestContract("Consumer", "Provider", "1.0.0"))
+	storage.SaveContract(createTestContract("Consumer", "Provider", "2.0.0"))
+	storage.UpdatePacticipant("Consumer", func(p *broker.Pacticipant) {
+		p.RecordDeployment("1.0.0", "production")
+	})
+	api := broker.NewAPI(storage)
+	server := httptest.NewServer(api.Handler())
+	defer server.Close()
+
+	post := func(t *testing.T, body string) *http.Response {
+		t.Helper()
+		resp, err := http.Post(server.URL+"/pacts/provider/Provider/for-verification", "application/json", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("resolves consumer version selectors", func(t *testing.T) {
+		resp := post(t, `{"consumerVersionSelectors": [{"deployed": true, "environment": "production"}, {"latest": true}]}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var pacts []broker.SelectedPact
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pacts))
+		assert.Equal(t, []broker.SelectedPact{
+			{Consumer: "Consumer", Provider: "Provider", Version: "1.0.0"},
+			{Consumer: "Consumer", Provider: "Provider", Version: "2.0.0"},
+		}, pacts)
+	})
+
+	t.Run("selects the latest versions without a body", func(t *testing.T) {
+		resp := post(t, "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var pacts []broker.SelectedPact
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pacts))
+		assert.Equal(t, []broker.SelectedPact{{Consumer: "Consumer", Provider: "Provider", Version: "2.0.0"}}, pacts)
+	})
+
+	t.Run("rejects invalid selectors", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post(t, `{"consumerVersionSelectors": [{}]}`).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, post(t, `invalid json`).StatusCode)
+	})
+}
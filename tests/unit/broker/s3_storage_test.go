@@ -318,3 +318,24 @@ func TestMockS3Client_ImplementsInterface(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, keys) // deleted above
 }
+
+func TestS3Storage_Pacticipants(t *testing.T) {
+	t.Run("persists pacticipant versions in the index", func(t *testing.T) {
+		mock := broker.NewMockS3Client()
+		storage := broker.NewS3Storage(mock, "test-bucket", "pacts/")
+		require.NoError(t, storage.SaveContract(createTestContract("Consumer", "Provider", "1.0.0")))
+		require.NoError(t, storage.UpdatePacticipant("Consumer", func(p *broker.Pacticipant) {
+			p.RecordDeployment("1.0.0", "production")
+		}))
+
+		// A new storage on the same bucket sees the pacticipant
+		reloaded := broker.NewS3Storage(mock, "test-bucket", "pacts/")
+		p, err := reloaded.GetPacticipant("Consumer")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"production"}, p.Version("1.0.0").DeployedTo)
+		assert.Equal(t, map[string][]string{"Consumer": {"1.0.0"}}, reloaded.ListConsumerVersions("Provider"))
+
+		_, err = reloaded.GetPacticipant("Unknown")
+		assert.ErrorIs(t, err, broker.ErrPacticipantNotFound)
+	})
+}
@@ -0,0 +1,138 @@
+package broker_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/broker"
+)
+
+func TestSelectPactsForVerification(t *testing.T) {
+	// Web publishes 1.9.0 on main, 2.0.0 on a feature branch and then
+	// 1.10.0 on main, so the last version in sort order is not the latest
+	newStorage := func(t *testing.T) *broker.MemoryStorage {
+		t.Helper()
+		storage := broker.NewMemoryStorage()
+		for _, version := range []string{"1.9.0", "2.0.0", "1.10.0"} {
+			require.NoError(t, storage.SaveContract(createTestContract("Web", "Provider", version)))
+		}
+		require.NoError(t, storage.SaveContract(createTestContract("Mobile", "Provider", "5.0.0")))
+		require.NoError(t, storage.SaveContract(createTestContract("Mobile", "Provider", "5.1.0")))
+		require.NoError(t, storage.SaveContract(createTestContract("Web", "Other", "9.0.0")))
+
+		require.NoError(t, storage.UpdatePacticipant("Web", func(p *broker.Pacticipant) {
+			p.AddBranch("1.9.0", "main")
+			p.AddBranch("2.0.0", "feature/login")
+			p.AddBranch("1.10.0", "main")
+			p.AddTag("1.9.0", "prod")
+			p.AddTag("1.10.0", "prod")
+			p.RecordDeployment("1.9.0", "production")
+			p.RecordDeployment("2.0.0", "staging")
+		}))
+		require.NoError(t, storage.UpdatePacticipant("Mobile", func(p *broker.Pacticipant) {
+			p.RecordRelease("5.0.0", "production")
+			p.RecordRelease("5.1.0", "production")
+		}))
+		return storage
+	}
+	versions := func(pacts []broker.SelectedPact) []string {
+		result := make([]string, len(pacts))
+		for i, p := range pacts {
+			result[i] = p.Consumer + "@" + p.Version
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		selectors []broker.ConsumerVersionSelector
+		expected  []string
+	}{
+		{
+			name:     "defaults to the latest version of each consumer",
+			expected: []string{"Mobile@5.1.0", "Web@1.10.0"},
+		},
+		{
+			name:      "selects the latest version by publication order",
+			selectors: []broker.ConsumerVersionSelector{{Consumer: "Web", Latest: true}},
+			expected:  []string{"Web@1.10.0"},
+		},
+		{
+			name:      "selects the latest version of a branch",
+			selectors: []broker.ConsumerVersionSelector{{Branch: "feature/login"}},
+			expected:  []string{"Web@2.0.0"},
+		},
+		{
+			name:      "selects the latest version of the main branch",
+			selectors: []broker.ConsumerVersionSelector{{MainBranch: true}},
+			expected:  []string{"Web@1.10.0"},
+		},
+		{
+			name:      "selects all versions with a tag",
+			selectors: []broker.ConsumerVersionSelector{{Tag: "prod"}},
+			expected:  []string{"Web@1.9.0", "Web@1.10.0"},
+		},
+		{
+			name:      "selects the latest version with a tag",
+			selectors: []broker.ConsumerVersionSelector{{Tag: "prod", Latest: true}},
+			expected:  []string{"Web@1.10.0"},
+		},
+		{
+			name:      "selects versions deployed to an environment",
+			selectors: []broker.ConsumerVersionSelector{{Deployed: true, Environment: "production"}},
+			expected:  []string{"Web@1.9.0"},
+		},
+		{
+			name:      "selects versions released to an environment",
+			selectors: []broker.ConsumerVersionSelector{{Released: true, Environment: "production"}},
+			expected:  []string{"Mobile@5.0.0", "Mobile@5.1.0"},
+		},
+		{
+			name:      "selects versions deployed or released to an environment",
+			selectors: []broker.ConsumerVersionSelector{{Environment: "production"}},
+			expected:  []string{"Mobile@5.0.0", "Mobile@5.1.0", "Web@1.9.0"},
+		},
+		{
+			name:      "selects versions deployed to any environment",
+			selectors: []broker.ConsumerVersionSelector{{Deployed: true}},
+			expected:  []string{"Web@1.9.0", "Web@2.0.0"},
+		},
+		{
+			name: "deduplicates versions selected more than once",
+			selectors: []broker.ConsumerVersionSelector{
+				{MainBranch: true},
+				{DeployedOrReleased: true, Environment: "production"},
+				{Tag: "prod"},
+			},
+			expected: []string{"Mobile@5.0.0", "Mobile@5.1.0", "Web@1.9.0", "Web@1.10.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pacts, err := broker.SelectPactsForVerification(newStorage(t), "Provider", tt.selectors)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, versions(pacts))
+		})
+	}
+
+	t.Run("replaces the version previously deployed to an environment", func(t *testing.T) {
+		storage := newStorage(t)
+		require.NoError(t, storage.UpdatePacticipant("Web", func(p *broker.Pacticipant) {
+			p.RecordDeployment("1.10.0", "production")
+		}))
+
+		pacts, err := broker.SelectPactsForVerification(storage, "Provider",
+			[]broker.ConsumerVersionSelector{{Deployed: true, Environment: "production"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Web@1.10.0"}, versions(pacts))
+	})
+
+	t.Run("rejects selectors without criteria", func(t *testing.T) {
+		_, err := broker.SelectPactsForVerification(newStorage(t), "Provider",
+			[]broker.ConsumerVersionSelector{{Consumer: "Web"}})
+		require.Error(t, err)
+	})
+}
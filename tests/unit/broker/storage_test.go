@@ -164,6 +164,58 @@ func TestStorage_RecordAndGetVerification(t *testing.T) {
 	})
 }
 
+func TestStorage_Pacticipants(t *testing.T) {
+	t.Run("records consumer versions in publication order", func(t *testing.T) {
+		storage := broker.NewMemoryStorage()
+		storage.SaveContract(createTestContract("Consumer", "Provider", "1.10.0"))
+		storage.SaveContract(createTestContract("Consumer", "Provider", "1.9.0"))
+		storage.SaveContract(createTestContract("Consumer", "Other", "1.10.0"))
+
+		p, err := storage.GetPacticipant("Consumer")
+		require.NoError(t, err)
+		require.Len(t, p.Versions, 2)
+		assert.Equal(t, "1.10.0", p.Versions[0].Number)
+		assert.Equal(t, "1.9.0", p.Versions[1].Number)
+	})
+
+	t.Run("updates pacticipants", func(t *testing.T) {
+		storage := broker.NewMemoryStorage()
+		err := storage.UpdatePacticipant("Consumer", func(p *broker.Pacticipant) {
+			p.AddBranch("1.0.0", "master")
+			p.AddTag("1.0.0", "prod")
+		})
+		require.NoError(t, err)
+
+		p, err := storage.GetPacticipant("Consumer")
+		require.NoError(t, err)
+		assert.Equal(t, "master", p.MainBranch)
+		assert.Equal(t, []string{"prod"}, p.Version("1.0.0").Tags)
+
+		// Returned pacticipants are copies
+		p.Version("1.0.0").Tags = nil
+		p, _ = storage.GetPacticipant("Consumer")
+		assert.Equal(t, []string{"prod"}, p.Version("1.0.0").Tags)
+	})
+
+	t.Run("returns error for unknown pacticipant", func(t *testing.T) {
+		_, err := broker.NewMemoryStorage().GetPacticipant("Unknown")
+		assert.ErrorIs(t, err, broker.ErrPacticipantNotFound)
+	})
+
+	t.Run("lists contract versions by consumer", func(t *testing.T) {
+		storage := broker.NewMemoryStorage()
+		storage.SaveContract(createTestContract("A", "Provider", "1.0.0"))
+		storage.SaveContract(createTestContract("A", "Provider", "2.0.0"))
+		storage.SaveContract(createTestContract("B", "Provider", "1.0.0"))
+		storage.SaveContract(createTestContract("C", "Other", "1.0.0"))
+
+		assert.Equal(t, map[string][]string{
+			"A": {"1.0.0", "2.0.0"},
+			"B": {"1.0.0"},
+		}, storage.ListConsumerVersions("Provider"))
+	})
+}
+
 func createTestContract(consumer, provider, version string) *contract.Contract {
 	return &contract.Contract{
 		Consumer: contract.Pacticipant{Name: consumer},
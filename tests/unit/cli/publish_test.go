@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jt-chihara/yakusoku/internal/broker"
 	"github.com/jt-chihara/yakusoku/internal/cli"
 )
 
@@ -169,6 +170,36 @@ func TestPublishCommand_Execute(t *testing.T) {
 		assert.NotEmpty(t, requestPath)
 	})
 
+	t.Run("records branch and tags in the broker", func(t *testing.T) {
+		storage := broker.NewMemoryStorage()
+		server := httptest.NewServer(broker.NewAPI(storage).Handler())
+		defer server.Close()
+
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "contract.json")
+		createPublishContract(t, contractPath)
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewPublishCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--broker-url", server.URL,
+			"--pact-file", contractPath,
+			"--consumer-version", "1.0.0",
+			"--branch", "feature/login",
+			"--tag", "beta",
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+
+		p, err := storage.GetPacticipant("Consumer")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"feature/login"}, p.Version("1.0.0").Branches)
+		assert.Equal(t, []string{"beta"}, p.Version("1.0.0").Tags)
+	})
+
 	t.Run("sends Authorization header when broker-token is provided", func(t *testing.T) {
 		var authHeader string
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,68 @@
+package cli_test
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/broker"
+	"github.com/jt-chihara/yakusoku/internal/cli"
+)
+
+func TestRecordCommands_Execute(t *testing.T) {
+	run := func(cmd *cobra.Command, args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	t.Run("records deployments and releases", func(t *testing.T) {
+		storage := broker.NewMemoryStorage()
+		server := httptest.NewServer(broker.NewAPI(storage).Handler())
+		defer server.Close()
+
+		output, err := run(cli.NewRecordDeploymentCommand(),
+			"--broker-url", server.URL, "--pacticipant", "Web", "--version", "1.0.0", "--environment", "production")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Web version 1.0.0 recorded as deployed to production")
+
+		_, err = run(cli.NewRecordDeploymentCommand(),
+			"--broker-url", server.URL, "--pacticipant", "Web", "--version", "1.1.0", "--environment", "production")
+		require.NoError(t, err)
+
+		output, err = run(cli.NewRecordReleaseCommand(),
+			"--broker-url", server.URL, "--pacticipant", "Web", "--version", "1.0.0", "--environment", "app-store")
+		require.NoError(t, err)
+		assert.Contains(t, output, "recorded as released to app-store")
+
+		p, err := storage.GetPacticipant("Web")
+		require.NoError(t, err)
+		assert.Empty(t, p.Version("1.0.0").DeployedTo)
+		assert.Equal(t, []string{"app-store"}, p.Version("1.0.0").ReleasedTo)
+		assert.Equal(t, []string{"production"}, p.Version("1.1.0").DeployedTo)
+	})
+
+	t.Run("returns error for missing environment", func(t *testing.T) {
+		_, err := run(cli.NewRecordDeploymentCommand(),
+			"--broker-url", "http://localhost:9292", "--pacticipant", "Web", "--version", "1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--environment is required")
+	})
+
+	t.Run("reports broker errors", func(t *testing.T) {
+		server := httptest.NewServer(broker.WrapWithAuth("secret", broker.NewAPI(broker.NewMemoryStorage()).Handler()))
+		defer server.Close()
+
+		_, err := run(cli.NewRecordReleaseCommand(),
+			"--broker-url", server.URL, "--pacticipant", "Web", "--version", "1.0.0", "--environment", "production")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 401")
+	})
+}
@@ -3,6 +3,7 @@ package cli_test
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -12,7 +13,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jt-chihara/yakusoku/internal/broker"
 	"github.com/jt-chihara/yakusoku/internal/cli"
+	"github.com/jt-chihara/yakusoku/internal/contract"
 )
 
 func TestVerifyCommand_Execute(t *testing.T) {
@@ -99,6 +102,202 @@ func TestVerifyCommand_Execute(t *testing.T) {
 		require.Error(t, err)
 	})
 
+	t.Run("returns error for parallel below 1", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "test.json")
+		os.WriteFile(contractPath, []byte("{}"), 0644)
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--provider-base-url", "http://localhost:8080",
+			"--pact-file", contractPath,
+			"--parallel", "0",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--parallel")
+	})
+
+	t.Run("waits for the provider before verifying", func(t *testing.T) {
+		ready := false
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				ready = true
+				return
+			}
+			if !ready {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id":1}`))
+		}))
+		defer provider.Close()
+
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "test.json")
+		contract := map[string]interface{}{
+			"consumer": map[string]interface{}{"name": "Consumer"},
+			"provider": map[string]interface{}{"name": "Provider"},
+			"interactions": []interface{}{
+				map[string]interface{}{
+					"description": "get user",
+					"request":     map[string]interface{}{"method": "GET", "path": "/users/1"},
+					"response":    map[string]interface{}{"status": 200, "body": map[string]interface{}{"id": 1}},
+				},
+			},
+			"metadata": map[string]interface{}{
+				"pactSpecification": map[string]interface{}{"version": "3.0.0"},
+			},
+		}
+		data, _ := json.Marshal(contract)
+		os.WriteFile(contractPath, data, 0644)
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--provider-base-url", provider.URL,
+			"--pact-file", contractPath,
+			"--wait-for-provider-url", provider.URL + "/health",
+			"--request-timeout", "5s",
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("customizes provider requests", func(t *testing.T) {
+		var received http.Header
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header
+			if r.URL.Path != "/users/2" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"id":1}`))
+		}))
+		defer provider.Close()
+
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "test.json")
+		contract := map[string]interface{}{
+			"consumer": map[string]interface{}{"name": "Consumer"},
+			"provider": map[string]interface{}{"name": "Provider"},
+			"interactions": []interface{}{
+				map[string]interface{}{
+					"description": "get user",
+					"request": map[string]interface{}{
+						"method":  "GET",
+						"path":    "/users/1",
+						"headers": map[string]interface{}{"Authorization": "Bearer placeholder"},
+					},
+					"response": map[string]interface{}{"status": 200, "body": map[string]interface{}{"id": 1}},
+				},
+			},
+			"metadata": map[string]interface{}{
+				"pactSpecification": map[string]interface{}{"version": "3.0.0"},
+			},
+		}
+		data, _ := json.Marshal(contract)
+		os.WriteFile(contractPath, data, 0644)
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--provider-base-url", provider.URL,
+			"--pact-file", contractPath,
+			"--custom-header", "Authorization: Bearer secret",
+			"--custom-header", "X-Tenant: acme",
+			"--request-filter-cmd", "sed 's#/users/1#/users/2#'",
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err, stdout.String())
+		assert.Equal(t, "Bearer secret", received.Get("Authorization"))
+		assert.Equal(t, "acme", received.Get("X-Tenant"))
+	})
+
+	t.Run("passes binary bodies to the request filter as base64", func(t *testing.T) {
+		var received []byte
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer provider.Close()
+
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "test.json")
+		contract := map[string]interface{}{
+			"consumer": map[string]interface{}{"name": "Consumer"},
+			"provider": map[string]interface{}{"name": "Provider"},
+			"interactions": []interface{}{
+				map[string]interface{}{
+					"description": "upload avatar",
+					"request": map[string]interface{}{
+						"method":  "PUT",
+						"path":    "/avatar",
+						"headers": map[string]interface{}{"Content-Type": "application/octet-stream"},
+						"body":    "AAEC/w==",
+					},
+					"response": map[string]interface{}{"status": 204},
+				},
+			},
+			"metadata": map[string]interface{}{
+				"pactSpecification": map[string]interface{}{"version": "3.0.0"},
+			},
+		}
+		data, _ := json.Marshal(contract)
+		os.WriteFile(contractPath, data, 0644)
+		filterInput := filepath.Join(tmpDir, "filter-input.json")
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--provider-base-url", provider.URL,
+			"--pact-file", contractPath,
+			"--request-filter-cmd", "tee " + filterInput,
+		})
+
+		err := cmd.Execute()
+		require.NoError(t, err, stdout.String())
+		assert.Equal(t, []byte{0x00, 0x01, 0x02, 0xff}, received)
+
+		input, err := os.ReadFile(filterInput)
+		require.NoError(t, err)
+		assert.Contains(t, string(input), `"body":"AAEC/w=="`)
+		assert.Contains(t, string(input), `"encoding":"base64"`)
+	})
+
+	t.Run("returns error for invalid custom header", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		contractPath := filepath.Join(tmpDir, "test.json")
+		os.WriteFile(contractPath, []byte("{}"), 0644)
+
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs([]string{
+			"--provider-base-url", "http://localhost:8080",
+			"--pact-file", contractPath,
+			"--custom-header", "Authorization",
+		})
+
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--custom-header")
+	})
+
 	t.Run("reports failure when verification fails", func(t *testing.T) {
 		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(404) // Wrong status
@@ -234,3 +433,276 @@ func TestVerifyCommand_Execute(t *testing.T) {
 		assert.Contains(t, output, "/users/1")
 	})
 }
+
+func TestVerifyCommand_MultipleContracts(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/users/1" {
+			w.Write([]byte(`{"id":1}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer provider.Close()
+
+	writeContract := func(t *testing.T, dir, consumer, providerName, path string) {
+		t.Helper()
+		contract := map[string]interface{}{
+			"consumer": map[string]interface{}{"name": consumer},
+			"provider": map[string]interface{}{"name": providerName},
+			"interactions": []interface{}{
+				map[string]interface{}{
+					"description": consumer + " gets a user",
+					"request":     map[string]interface{}{"method": "GET", "path": path},
+					"response":    map[string]interface{}{"status": 200, "body": map[string]interface{}{"id": 1}},
+				},
+			},
+			"metadata": map[string]interface{}{
+				"pactSpecification": map[string]interface{}{"version": "3.0.0"},
+			},
+		}
+		data, _ := json.Marshal(contract)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, consumer+"-"+providerName+".json"), data, 0644))
+	}
+	run := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(append([]string{"--provider-base-url", provider.URL}, args...))
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	t.Run("verifies all contracts in a directory for a provider", func(t *testing.T) {
+		dir := t.TempDir()
+		writeContract(t, dir, "Web", "UserService", "/users/1")
+		writeContract(t, dir, "Mobile", "UserService", "/users/1")
+		writeContract(t, dir, "Web", "OrderService", "/orders/1")
+
+		output, err := run("--pact-dir", dir, "--provider", "UserService")
+		require.NoError(t, err)
+		assert.Contains(t, output, "Verifying a pact between Mobile and UserService")
+		assert.Contains(t, output, "Verifying a pact between Web and UserService")
+		assert.NotContains(t, output, "OrderService")
+		assert.Contains(t, output, "Total: 2 contracts (0 failed), 2 interactions passed, 0 failed")
+	})
+
+	t.Run("aggregates failures from repeated files and globs", func(t *testing.T) {
+		dir := t.TempDir()
+		writeContract(t, dir, "Web", "UserService", "/users/1")
+		writeContract(t, dir, "Mobile", "UserService", "/users/2")
+		writeContract(t, dir, "Batch", "UserService", "/users/3")
+
+		output, err := run(
+			"--pact-file", filepath.Join(dir, "Web-*.json"),
+			"--pact-file", filepath.Join(dir, "Mobile-UserService.json"),
+			"--pact-file", filepath.Join(dir, "*-UserService.json"),
+		)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 interactions failed")
+		assert.Contains(t, output, "Total: 3 contracts (2 failed), 1 interactions passed, 2 failed")
+	})
+
+	t.Run("returns error when no contract matches the provider", func(t *testing.T) {
+		dir := t.TempDir()
+		writeContract(t, dir, "Web", "OrderService", "/orders/1")
+
+		_, err := run("--pact-dir", dir, "--provider", "UserService")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no contracts found for provider "UserService"`)
+	})
+
+	t.Run("returns error without pact file or directory", func(t *testing.T) {
+		_, err := run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--pact-dir")
+	})
+}
+
+func TestVerifyCommand_Broker(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Write([]byte(`{"id":1}`))
+		case "/v1/users/1":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer provider.Close()
+
+	newBroker := func(t *testing.T) (*httptest.Server, *broker.MemoryStorage) {
+		t.Helper()
+		storage := broker.NewMemoryStorage()
+		publish := func(consumer, providerName, version, path string) {
+			require.NoError(t, storage.SaveContract(&contract.Contract{
+				Consumer: contract.Pacticipant{Name: consumer},
+				Provider: contract.Pacticipant{Name: providerName},
+				Interactions: []contract.Interaction{
+					{
+						Description: consumer + " gets a user",
+						Request:     contract.Request{Method: "GET", Path: path},
+						Response:    contract.Response{Status: 200, Body: map[string]interface{}{"id": 1}},
+					},
+				},
+				Metadata: contract.Metadata{PactSpecification: contract.PactSpec{Version: version}},
+			}))
+		}
+		publish("Web", "UserService", "1.0.0", "/v1/users/1")
+		publish("Web", "UserService", "2.0.0", "/users/1")
+		publish("Mobile", "UserService", "1.0.0", "/users/1")
+		publish("Web", "OrderService", "1.0.0", "/orders/1")
+		return httptest.NewServer(broker.NewAPI(storage).Handler()), storage
+	}
+	run := func(args ...string) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := cli.NewVerifyCommand()
+		cmd.SetOut(&stdout)
+		cmd.SetErr(&stderr)
+		cmd.SetArgs(append([]string{"--provider-base-url", provider.URL}, args...))
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	t.Run("verifies the latest contracts of the provider", func(t *testing.T) {
+		server, _ := newBroker(t)
+		defer server.Close()
+
+		output, err := run("--broker-url", server.URL, "--provider", "UserService")
+		require.NoError(t, err, output)
+		assert.Contains(t, output, "Verifying a pact between Mobile and UserService")
+		assert.Contains(t, output, "Verifying a pact between Web and UserService")
+		assert.NotContains(t, output, "OrderService")
+		assert.Contains(t, output, "Total: 2 contracts (0 failed)")
+	})
+
+	t.Run("fetches specific consumer versions", func(t *testing.T) {
+		server, _ := newBroker(t)
+		defer server.Close()
+
+		output, err := run("--broker-url", server.URL, "--provider", "UserService", "--consumer-version", "Web=1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, output, "Total: 2 contracts (1 failed)")
+	})
+
+	t.Run("sends the broker token", func(t *testing.T) {
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		_, err := run("--broker-url", server.URL, "--broker-token", "secret", "--provider", "UserService")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no contracts found for provider "UserService"`)
+		assert.Equal(t, "Bearer secret", authorization)
+	})
+
+	t.Run("reports contracts missing from the broker", func(t *testing.T) {
+		server, _ := newBroker(t)
+		defer server.Close()
+
+		_, err := run("--broker-url", server.URL, "--provider", "UserService", "--consumer-version", "Web=9.9.9")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch contract Web version 9.9.9")
+		assert.Contains(t, err.Error(), "status 404")
+	})
+
+	t.Run("verifies the versions selected by consumer version selectors", func(t *testing.T) {
+		server, storage := newBroker(t)
+		defer server.Close()
+		require.NoError(t, storage.UpdatePacticipant("Web", func(p *broker.Pacticipant) {
+			p.RecordDeployment("2.0.0", "production")
+		}))
+
+		output, err := run("--broker-url", server.URL, "--provider", "UserService",
+			"--consumer-version-selector", `{"deployed": true, "environment": "production"}`,
+			"--publish-verification-results", "--provider-app-version", "3.1.0")
+		require.NoError(t, err, output)
+		assert.Contains(t, output, "Verifying a pact between Web and UserService")
+		assert.NotContains(t, output, "Mobile")
+		assert.Contains(t, output, "Verification results published for Web version 2.0.0")
+	})
+
+	t.Run("rejects invalid consumer version selectors", func(t *testing.T) {
+		_, err := run("--broker-url", "http://localhost:9292", "--provider", "UserService",
+			"--consumer-version-selector", `{"environmnet": "production"}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid --consumer-version-selector")
+
+		_, err = run("--broker-url", "http://localhost:9292", "--provider", "UserService",
+			"--consumer-version-selector", `{"latest": true}`, "--consumer-version", "Web=1.0.0")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "cannot be used together")
+	})
+
+	t.Run("publishes verification results for the verified versions", func(t *testing.T) {
+		server, storage := newBroker(t)
+		defer server.Close()
+
+		output, err := run("--broker-url", server.URL, "--provider", "UserService",
+			"--consumer-version", "Web=1.0.0",
+			"--publish-verification-results", "--provider-app-version", "3.1.0")
+		require.Error(t, err)
+		assert.Contains(t, output, "Verification results published for Web version 1.0.0")
+		assert.Contains(t, output, "Verification results published for Mobile version 1.0.0")
+
+		success, exists := storage.GetVerification("Web", "UserService", "1.0.0")
+		assert.True(t, exists)
+		assert.False(t, success)
+		success, exists = storage.GetVerification("Mobile", "UserService", "1.0.0")
+		assert.True(t, exists)
+		assert.True(t, success)
+		_, exists = storage.GetVerification("Web", "UserService", "2.0.0")
+		assert.False(t, exists)
+	})
+
+	t.Run("posts per-interaction results", func(t *testing.T) {
+		var received map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/pacts/provider/UserService":
+				w.Write([]byte(`[{"consumer":"Web","provider":"UserService","version":"2.0.0"}]`))
+			case "/pacts/provider/UserService/consumer/Web/version/2.0.0":
+				w.Write([]byte(`{"consumer":{"name":"Web"},"provider":{"name":"UserService"},"interactions":[
+					{"description":"get user","request":{"method":"GET","path":"/users/1"},"response":{"status":201}}]}`))
+			case "/pacts/provider/UserService/consumer/Web/version/2.0.0/verification-results":
+				require.Equal(t, http.MethodPost, r.Method)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(http.StatusCreated)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		_, err := run("--broker-url", server.URL, "--provider", "UserService",
+			"--publish-verification-results", "--provider-app-version", "3.1.0")
+		require.Error(t, err)
+
+		require.NotNil(t, received)
+		assert.Equal(t, false, received["success"])
+		assert.Equal(t, "3.1.0", received["providerVersion"])
+		testResults := received["testResults"].([]interface{})
+		require.Len(t, testResults, 1)
+		testResult := testResults[0].(map[string]interface{})
+		assert.Equal(t, "get user", testResult["interactionDescription"])
+		assert.Equal(t, false, testResult["success"])
+		mismatch := testResult["mismatches"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "status", mismatch["kind"])
+	})
+
+	t.Run("requires a provider version to publish results", func(t *testing.T) {
+		_, err := run("--broker-url", "http://localhost:9292", "--provider", "UserService", "--publish-verification-results")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--provider-app-version is required")
+	})
+
+	t.Run("requires a provider", func(t *testing.T) {
+		_, err := run("--broker-url", "http://localhost:9292")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--provider is required")
+	})
+}
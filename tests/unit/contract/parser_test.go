@@ -3,12 +3,14 @@ package contract_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
 )
 
 func TestParser_ParseFile(t *testing.T) {
@@ -171,6 +173,62 @@ func TestParser_ParseBytes(t *testing.T) {
 		assert.Equal(t, "Provider", c.Provider.Name)
 	})
 
+	t.Run("parse v4 status matching rules", func(t *testing.T) {
+		// As written by pact-jvm for a successStatus() response
+		content := []byte(`{
+			"consumer": {"name": "OrderWeb"},
+			"provider": {"name": "OrderService"},
+			"interactions": [
+				{
+					"type": "Synchronous/HTTP",
+					"key": "4f1f7d3a",
+					"description": "a request to cancel an order",
+					"pending": false,
+					"providerStates": [{"name": "order 42 exists"}],
+					"request": {"method": "DELETE", "path": "/orders/42"},
+					"response": {
+						"status": 204,
+						"matchingRules": {
+							"status": {
+								"combine": "AND",
+								"matchers": [{"match": "statusCode", "status": "success"}]
+							}
+						}
+					}
+				},
+				{
+					"type": "Synchronous/HTTP",
+					"key": "9b2c6e10",
+					"description": "a request for an order",
+					"pending": false,
+					"request": {"method": "GET", "path": "/orders/42"},
+					"response": {"status": 200}
+				}
+			],
+			"metadata": {"pactSpecification": {"version": "4.0"}}
+		}`)
+
+		c, err := contract.NewParser().ParseBytes(content)
+		require.NoError(t, err)
+		require.Len(t, c.Interactions, 2)
+
+		status := c.Interactions[0].Response.MatchingRules.Status
+		require.NotNil(t, status)
+		assert.Equal(t, "AND", status.Combine)
+		require.Len(t, status.Matchers, 1)
+		assert.Equal(t, "statusCode", status.Matchers[0].Match)
+		assert.Equal(t, "success", status.Matchers[0].Status)
+		assert.Nil(t, c.Interactions[1].Response.MatchingRules.Status)
+
+		result, err := matcher.NewComparator().CompareStatus(204, 200, status)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		data, err := contract.NewWriter().WriteBytes(c)
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(data), `"status": {`))
+	})
+
 	t.Run("parse empty bytes", func(t *testing.T) {
 		parser := contract.NewParser()
 		_, err := parser.ParseBytes([]byte{})
@@ -207,6 +207,36 @@ func TestRequest_JSONMarshaling(t *testing.T) {
 	})
 }
 
+func TestQuery_UnmarshalJSON(t *testing.T) {
+	t.Run("v3 map with repeated values", func(t *testing.T) {
+		var r contract.Request
+		err := json.Unmarshal([]byte(`{"method":"GET","path":"/users","query":{"id":["1","2"],"status":"active"}}`), &r)
+		require.NoError(t, err)
+
+		assert.Equal(t, contract.Query{"id": {"1", "2"}, "status": {"active"}}, r.Query)
+	})
+
+	t.Run("v2 raw query string", func(t *testing.T) {
+		var r contract.Request
+		err := json.Unmarshal([]byte(`{"method":"GET","path":"/users","query":"id=1&id=2&name=Jane+Doe"}`), &r)
+		require.NoError(t, err)
+
+		assert.Equal(t, contract.Query{"id": {"1", "2"}, "name": {"Jane Doe"}}, r.Query)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		var r contract.Request
+		err := json.Unmarshal([]byte(`{"method":"GET","path":"/users","query":{"id":1}}`), &r)
+		assert.Error(t, err)
+	})
+}
+
+func TestQuery_Encode(t *testing.T) {
+	q := contract.Query{"status": {"active"}, "id": {"2", "1"}, "q": {"a b&c"}}
+	assert.Equal(t, "id=2&id=1&q=a+b%26c&status=active", q.Encode())
+	assert.Equal(t, "", contract.Query(nil).Encode())
+}
+
 func TestResponse_JSONMarshaling(t *testing.T) {
 	t.Run("marshal response with all fields", func(t *testing.T) {
 		r := contract.Response{
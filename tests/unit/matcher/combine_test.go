@@ -0,0 +1,148 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+const uuidRegex = `[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`
+
+func TestComparator_Combine(t *testing.T) {
+	idRules := func(combine string) map[string]contract.MatcherSet {
+		return map[string]contract.MatcherSet{
+			"$.id": {
+				Combine: combine,
+				Matchers: []contract.Matcher{
+					{Match: "regex", Regex: uuidRegex},
+					{Match: "integer"},
+				},
+			},
+		}
+	}
+	expected := map[string]interface{}{"id": "3f1c2b9e-1a2b-4c3d-8e9f-0a1b2c3d4e5f"}
+
+	t.Run("OR passes when any matcher matches", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		for _, id := range []interface{}{"3f1c2b9e-1a2b-4c3d-8e9f-0a1b2c3d4e5f", float64(42)} {
+			result, err := c.CompareBody(expected, map[string]interface{}{"id": id}, idRules("OR"))
+			require.NoError(t, err)
+			assert.True(t, result.Matched, "%v", id)
+		}
+	})
+
+	t.Run("OR reports the best attempt when nothing matches", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareBody(expected, map[string]interface{}{"id": "not-a-uuid"}, idRules("OR"))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "to match")
+		assert.NotContains(t, result.Diff, "integer")
+
+		result, err = c.CompareBody(expected, map[string]interface{}{"id": float64(1.5)}, idRules("OR"))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "expected an integer")
+	})
+
+	t.Run("AND is the default and reports every failure", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		for _, combine := range []string{"", "AND"} {
+			result, err := c.CompareBody(expected, map[string]interface{}{"id": float64(42)}, idRules(combine))
+			require.NoError(t, err)
+			assert.False(t, result.Matched)
+			assert.Contains(t, result.Diff, "to match")
+		}
+
+		rules := map[string]contract.MatcherSet{
+			"$.id": {
+				Matchers: []contract.Matcher{
+					{Match: "regex", Regex: `\d+`},
+					{Match: "type"},
+				},
+			},
+		}
+		result, err := c.CompareBody(map[string]interface{}{"id": "1"}, map[string]interface{}{"id": true}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "to match")
+		assert.Contains(t, result.Diff, "expected type string")
+	})
+
+	t.Run("combine is case insensitive", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareBody(expected, map[string]interface{}{"id": float64(42)}, idRules("or"))
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("unsupported combine returns error", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		_, err := c.CompareBody(expected, map[string]interface{}{"id": float64(42)}, idRules("XOR"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported combine")
+	})
+
+	t.Run("OR applies to headers", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"X-Request-Id": {
+				Combine: "OR",
+				Matchers: []contract.Matcher{
+					{Match: "regex", Regex: uuidRegex},
+					{Match: "regex", Regex: `\d+`},
+				},
+			},
+		}
+
+		result, err := c.CompareHeaders(
+			map[string]interface{}{"X-Request-Id": "1"},
+			map[string]interface{}{"X-Request-Id": "12345"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("OR with null accepts nullable objects and arrays", func(t *testing.T) {
+		c := matcher.NewComparator()
+		nullable := contract.MatcherSet{
+			Combine:  "OR",
+			Matchers: []contract.Matcher{{Match: "type"}, {Match: "null"}},
+		}
+		rules := map[string]contract.MatcherSet{"$.address": nullable, "$.tags": nullable}
+		expected := map[string]interface{}{
+			"address": map[string]interface{}{"street": "Main St"},
+			"tags":    []interface{}{"vip"},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{"address": nil, "tags": nil}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"address": map[string]interface{}{"street": "Elm St"},
+			"tags":    []interface{}{"new", "trial"},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"address": map[string]interface{}{"street": float64(1)},
+			"tags":    "vip",
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.address.street")
+		assert.Contains(t, result.Diff, "$.tags")
+	})
+}
@@ -21,6 +21,14 @@ func TestNewComparator(t *testing.T) {
 		assert.NotNil(t, m)
 		assert.Equal(t, "equality", m.Name())
 	})
+
+	t.Run("registers type matcher", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		m, ok := c.GetMatcher("type")
+		assert.True(t, ok)
+		assert.Equal(t, "type", m.Name())
+	})
 }
 
 func TestComparator_RegisterMatcher(t *testing.T) {
@@ -75,7 +83,7 @@ func TestComparator_Compare(t *testing.T) {
 		assert.Contains(t, result.Diff, "expected")
 	})
 
-	t.Run("uses equality matching with body rules (fallback behavior)", func(t *testing.T) {
+	t.Run("falls back to equality where no body rule applies", func(t *testing.T) {
 		c := matcher.NewComparator()
 		rules := contract.MatchingRules{
 			Body: map[string]contract.MatcherSet{
@@ -87,7 +95,7 @@ func TestComparator_Compare(t *testing.T) {
 			},
 		}
 
-		// Even with rules, current implementation falls back to equality
+		// The rule targets $.name, so the root value is compared by equality
 		result, err := c.Compare("test", "test", rules)
 		require.NoError(t, err)
 		assert.True(t, result.Matched)
@@ -149,7 +157,7 @@ func TestComparator_CompareBody(t *testing.T) {
 		assert.False(t, result.Matched)
 	})
 
-	t.Run("uses equality matching with rules (fallback behavior)", func(t *testing.T) {
+	t.Run("falls back to equality where no rule applies", func(t *testing.T) {
 		c := matcher.NewComparator()
 		rules := map[string]contract.MatcherSet{
 			"$.name": {
@@ -220,6 +228,200 @@ func TestComparator_CompareBody(t *testing.T) {
 	})
 }
 
+// alwaysMatcher matches any value. It lets the tests observe which rule
+// path was applied without depending on the built-in matchers.
+type alwaysMatcher struct{}
+
+func (m *alwaysMatcher) Name() string { return "always" }
+
+func (m *alwaysMatcher) Match(_, _ interface{}) (*matcher.MatchResult, error) {
+	return &matcher.MatchResult{Matched: true}, nil
+}
+
+func newAlwaysComparator() *matcher.Comparator {
+	c := matcher.NewComparator()
+	c.RegisterMatcher(&alwaysMatcher{})
+	return c
+}
+
+func ruleSet(names ...string) contract.MatcherSet {
+	set := contract.MatcherSet{}
+	for _, name := range names {
+		set.Matchers = append(set.Matchers, contract.Matcher{Match: name})
+	}
+	return set
+}
+
+func TestComparator_CompareBody_MatchingRules(t *testing.T) {
+	t.Run("applies rule to the named field only", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.user.name": ruleSet("always"),
+		}
+
+		expected := map[string]interface{}{
+			"user": map[string]interface{}{"id": float64(1), "name": "John"},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"user": map[string]interface{}{"id": float64(1), "name": "Jane"},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"user": map[string]interface{}{"id": float64(2), "name": "Jane"},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.user.id")
+		assert.NotContains(t, result.Diff, "$.user.name")
+	})
+
+	t.Run("wildcard index applies to every element", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.items[*].id": ruleSet("always"),
+		}
+
+		expected := map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "a"},
+				map[string]interface{}{"id": float64(2), "name": "b"},
+			},
+		}
+		actual := map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(10), "name": "a"},
+				map[string]interface{}{"id": float64(20), "name": "x"},
+			},
+		}
+
+		result, err := c.CompareBody(expected, actual, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Equal(t, "$.items[1].name: expected b (string), got x (string)", result.Diff)
+	})
+
+	t.Run("rules apply to descendants of the named path", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.user": ruleSet("always"),
+		}
+
+		expected := map[string]interface{}{
+			"user": map[string]interface{}{"id": float64(1), "name": "John"},
+		}
+		actual := map[string]interface{}{
+			"user": map[string]interface{}{"id": float64(2), "name": "Jane"},
+		}
+
+		result, err := c.CompareBody(expected, actual, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("most specific rule wins", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.items":       ruleSet("always"),
+			"$.items[*].id": ruleSet("equality"),
+		}
+
+		expected := map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "a"},
+			},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "changed"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(2), "name": "a"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.items[0].id")
+	})
+
+	t.Run("supports bracket notation for field names", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$['first name']": ruleSet("always"),
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"first name": "John"},
+			map[string]interface{}{"first name": "Jane"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("supports v2 $.body prefixed paths", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.body.name": ruleSet("always"),
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"name": "John"},
+			map[string]interface{}{"name": "Jane"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("reports missing fields and type mismatches", func(t *testing.T) {
+		c := newAlwaysComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.name": ruleSet("always"),
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"name": "John", "tags": []interface{}{"a"}},
+			map[string]interface{}{"tags": "a"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.name: missing field")
+		assert.Contains(t, result.Diff, "$.tags: expected array, got string")
+	})
+
+	t.Run("returns error for invalid rule path", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"name": ruleSet("equality"),
+		}
+
+		_, err := c.CompareBody("a", "a", rules)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid matching rule path")
+	})
+
+	t.Run("returns error for unsupported matcher", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$": ruleSet("nonexistent"),
+		}
+
+		_, err := c.CompareBody("a", "a", rules)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported matcher")
+	})
+}
+
 func TestComparator_CompareHeaders(t *testing.T) {
 	t.Run("both nil headers match", func(t *testing.T) {
 		c := matcher.NewComparator()
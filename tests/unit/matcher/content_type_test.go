@@ -0,0 +1,73 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func contentTypeRule(value string) contract.Matcher {
+	return contract.Matcher{Match: "contentType", Value: value}
+}
+
+func TestContentTypeMatcher_MatchRule(t *testing.T) {
+	m := matcher.NewContentTypeMatcher()
+	assert.Equal(t, "contentType", m.Name())
+
+	t.Run("detects binary and markup types", func(t *testing.T) {
+		cases := []struct {
+			contentType string
+			body        interface{}
+		}{
+			{"application/pdf", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"},
+			{"image/png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")},
+			{"text/html; charset=utf-8", "<!DOCTYPE html><html><body>hi</body></html>"},
+			{"application/xml", `<?xml version="1.0"?><user/>`},
+			{"application/json", `{"id":1}`},
+			{"text/csv", "id,name\n1,Jane\n"},
+		}
+		for _, tc := range cases {
+			result, err := m.MatchRule(contentTypeRule(tc.contentType), nil, tc.body)
+			require.NoError(t, err)
+			assert.True(t, result.Matched, "%s: %s", tc.contentType, result.Diff)
+		}
+	})
+
+	t.Run("fails when the detected type differs", func(t *testing.T) {
+		result, err := m.MatchRule(contentTypeRule("application/pdf"), nil, "<html><body>error</body></html>")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "expected application/pdf content, detected text/html")
+	})
+
+	t.Run("fails for non-string values", func(t *testing.T) {
+		result, err := m.MatchRule(contentTypeRule("application/json"), nil, map[string]interface{}{})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+
+	t.Run("requires a value", func(t *testing.T) {
+		_, err := m.MatchRule(contract.Matcher{Match: "contentType"}, nil, "text")
+		assert.Error(t, err)
+	})
+}
+
+func TestComparator_ContentType(t *testing.T) {
+	c := matcher.NewComparator()
+	rules := map[string]contract.MatcherSet{
+		"$": {Matchers: []contract.Matcher{contentTypeRule("application/pdf")}},
+	}
+
+	result, err := c.CompareBody("%PDF-1.4", "%PDF-1.7\nbinary", rules)
+	require.NoError(t, err)
+	assert.True(t, result.Matched, result.Diff)
+
+	result, err = c.CompareBody("%PDF-1.4", "not a pdf", rules)
+	require.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.Contains(t, result.Diff, "$: expected application/pdf content")
+}
@@ -0,0 +1,148 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestJavaLayout(t *testing.T) {
+	cases := []struct {
+		pattern string
+		layout  string
+	}{
+		{"yyyy-MM-dd", "2006-01-02"},
+		{"HH:mm:ss", "15:04:05"},
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSX", "2006-01-02T15:04:05.000Z07"},
+		{"yyyy-MM-dd'T'HH:mm:ssXXX", "2006-01-02T15:04:05Z07:00"},
+		{"EEE, dd MMM yyyy HH:mm:ss z", "Mon, 02 Jan 2006 15:04:05 MST"},
+		{"h:mm a", "3:04 PM"},
+		{"dd/MM/yy", "02/01/06"},
+		{"HH 'o''clock'", "15 o'clock"},
+	}
+	for _, tc := range cases {
+		layout, err := matcher.JavaLayout(tc.pattern)
+		require.NoError(t, err, tc.pattern)
+		assert.Equal(t, tc.layout, layout, tc.pattern)
+	}
+
+	t.Run("unsupported letter returns error", func(t *testing.T) {
+		_, err := matcher.JavaLayout("yyyy-QQ")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported pattern letter")
+	})
+
+	t.Run("fraction without separator returns error", func(t *testing.T) {
+		_, err := matcher.JavaLayout("yyyyMMddHHmmssSSS")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fraction of second")
+	})
+
+	t.Run("unterminated quote returns error", func(t *testing.T) {
+		_, err := matcher.JavaLayout("yyyy'T")
+		require.Error(t, err)
+	})
+}
+
+func TestDateTimeMatcher_MatchRule(t *testing.T) {
+	t.Run("matcher names", func(t *testing.T) {
+		assert.Equal(t, "date", matcher.NewDateMatcher().Name())
+		assert.Equal(t, "time", matcher.NewTimeMatcher().Name())
+		assert.Equal(t, "timestamp", matcher.NewTimestampMatcher().Name())
+	})
+
+	t.Run("timestamp in Java format matches", func(t *testing.T) {
+		m := matcher.NewTimestampMatcher()
+		rule := contract.Matcher{Match: "timestamp", Format: "yyyy-MM-dd'T'HH:mm:ss.SSSX"}
+
+		for _, actual := range []string{"2024-03-01T12:34:56.789Z", "2025-12-31T23:59:59.000+09"} {
+			result, err := m.MatchRule(rule, "", actual)
+			require.NoError(t, err)
+			assert.True(t, result.Matched, actual)
+		}
+	})
+
+	t.Run("single-letter offset accepts hours and minutes", func(t *testing.T) {
+		rule := contract.Matcher{Match: "timestamp", Format: "yyyy-MM-dd'T'HH:mm:ss.SSSX"}
+		result, err := matcher.NewTimestampMatcher().MatchRule(rule, "", "2024-05-01T10:00:00.123+0530")
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		rule = contract.Matcher{Match: "timestamp", Format: "yyyy-MM-dd'T'HH:mm:ssx"}
+		result, err = matcher.NewTimestampMatcher().MatchRule(rule, "", "2024-05-01T10:00:00-0330")
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+	})
+
+	t.Run("timestamp in wrong format fails", func(t *testing.T) {
+		m := matcher.NewTimestampMatcher()
+		rule := contract.Matcher{Match: "timestamp", Format: "yyyy-MM-dd'T'HH:mm:ss.SSSX"}
+
+		result, err := m.MatchRule(rule, "", "2024-03-01 12:34:56")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "yyyy-MM-dd'T'HH:mm:ss.SSSX")
+	})
+
+	t.Run("date and time with default formats", func(t *testing.T) {
+		result, err := matcher.NewDateMatcher().Match("", "2024-02-29")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = matcher.NewDateMatcher().Match("", "2023-02-29")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+
+		result, err = matcher.NewTimeMatcher().Match("", "08:15:00")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = matcher.NewTimestampMatcher().Match("", "2024-03-01T12:34:56.5+09:00")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("non-string value fails", func(t *testing.T) {
+		result, err := matcher.NewDateMatcher().Match("", float64(20240101))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "got number")
+	})
+
+	t.Run("invalid format returns error", func(t *testing.T) {
+		rule := contract.Matcher{Match: "date", Format: "yyyy-QQ"}
+		_, err := matcher.NewDateMatcher().MatchRule(rule, "", "2024-01")
+		require.Error(t, err)
+	})
+}
+
+func TestComparator_DateTimeRules(t *testing.T) {
+	c := matcher.NewComparator()
+	rules := map[string]contract.MatcherSet{
+		"$.createdAt": {Matchers: []contract.Matcher{{Match: "timestamp", Format: "yyyy-MM-dd'T'HH:mm:ssXXX"}}},
+		"$.birthday":  {Matchers: []contract.Matcher{{Match: "date", Format: "dd/MM/yyyy"}}},
+	}
+	expected := map[string]interface{}{
+		"createdAt": "2020-01-01T00:00:00Z",
+		"birthday":  "01/01/1990",
+	}
+
+	result, err := c.CompareBody(expected, map[string]interface{}{
+		"createdAt": "2026-10-16T09:30:00+09:00",
+		"birthday":  "24/12/1985",
+	}, rules)
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+
+	result, err = c.CompareBody(expected, map[string]interface{}{
+		"createdAt": "2026-10-16T09:30:00+09:00",
+		"birthday":  "1985-12-24",
+	}, rules)
+	require.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.Contains(t, result.Diff, "$.birthday")
+}
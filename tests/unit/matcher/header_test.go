@@ -0,0 +1,103 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestComparator_CompareHeaders_Normalization(t *testing.T) {
+	c := matcher.NewComparator()
+
+	cases := []struct {
+		name     string
+		expected map[string]interface{}
+		actual   map[string]interface{}
+		matched  bool
+	}{
+		{
+			name:     "header names ignore case",
+			expected: map[string]interface{}{"content-type": "application/json"},
+			actual:   map[string]interface{}{"Content-Type": "application/json"},
+			matched:  true,
+		},
+		{
+			name:     "comma separated values ignore spacing",
+			expected: map[string]interface{}{"Vary": "Accept, Accept-Encoding"},
+			actual:   map[string]interface{}{"Vary": "Accept,Accept-Encoding"},
+			matched:  true,
+		},
+		{
+			name:     "comma separated values keep order",
+			expected: map[string]interface{}{"Vary": "Accept, Accept-Encoding"},
+			actual:   map[string]interface{}{"Vary": "Accept-Encoding, Accept"},
+			matched:  false,
+		},
+		{
+			name:     "content type parameters ignore order and charset case",
+			expected: map[string]interface{}{"Content-Type": "multipart/mixed; charset=UTF-8; boundary=abc"},
+			actual:   map[string]interface{}{"Content-Type": "multipart/mixed;boundary=abc; charset=utf-8"},
+			matched:  true,
+		},
+		{
+			name:     "list values match comma separated values",
+			expected: map[string]interface{}{"Cache-Control": []interface{}{"no-cache", "no-store"}},
+			actual:   map[string]interface{}{"Cache-Control": "no-cache, no-store"},
+			matched:  true,
+		},
+		{
+			name:     "content type allows extra actual parameters",
+			expected: map[string]interface{}{"Content-Type": "application/json"},
+			actual:   map[string]interface{}{"Content-Type": "application/json; charset=utf-8"},
+			matched:  true,
+		},
+		{
+			name:     "content type parameter values must match",
+			expected: map[string]interface{}{"Content-Type": "text/plain; charset=utf-8"},
+			actual:   map[string]interface{}{"Content-Type": "text/plain; charset=iso-8859-1"},
+			matched:  false,
+		},
+		{
+			name:     "content type expected parameters must be present",
+			expected: map[string]interface{}{"Content-Type": "application/json; charset=utf-8"},
+			actual:   map[string]interface{}{"Content-Type": "application/json"},
+			matched:  false,
+		},
+		{
+			name:     "content type media types must match",
+			expected: map[string]interface{}{"Content-Type": "application/json"},
+			actual:   map[string]interface{}{"Content-Type": "text/plain"},
+			matched:  false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := c.CompareHeaders(tc.expected, tc.actual, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.matched, result.Matched, result.Diff)
+		})
+	}
+
+	t.Run("rules apply to headers found ignoring case", func(t *testing.T) {
+		result, err := c.CompareHeaders(
+			map[string]interface{}{"x-request-id": "abc-1"},
+			map[string]interface{}{"X-Request-Id": "xyz-9"},
+			map[string]contract.MatcherSet{
+				"X-Request-ID": {Matchers: []contract.Matcher{{Match: "regex", Regex: `[a-z]+-\d`}}},
+			},
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+	})
+}
+
+func TestHeaderValue(t *testing.T) {
+	assert.Equal(t, "no-cache", matcher.HeaderValue("no-cache"))
+	assert.Equal(t, "no-cache, no-store", matcher.HeaderValue([]interface{}{"no-cache", "no-store"}))
+	assert.Equal(t, "gzip, br", matcher.HeaderValue([]string{"gzip", "br"}))
+	assert.Equal(t, "42", matcher.HeaderValue(42))
+}
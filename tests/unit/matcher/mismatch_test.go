@@ -0,0 +1,73 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestMismatch_String(t *testing.T) {
+	cases := []struct {
+		mismatch matcher.Mismatch
+		want     string
+	}{
+		{matcher.Mismatch{Kind: matcher.KindBody, Path: "$.items[0].id", Message: "missing field"}, "$.items[0].id: missing field"},
+		{matcher.Mismatch{Kind: matcher.KindHeader, Path: "Accept", Message: "missing header"}, "header Accept: missing header"},
+		{matcher.Mismatch{Kind: matcher.KindQuery, Path: "page", Message: "expected [1], got [2]"}, "query page: expected [1], got [2]"},
+		{matcher.Mismatch{Kind: matcher.KindStatus, Message: "expected 200, got 500"}, "status: expected 200, got 500"},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.want, tc.mismatch.String())
+	}
+}
+
+func TestComparator_Mismatches(t *testing.T) {
+	c := matcher.NewComparator()
+
+	t.Run("body mismatches carry path, values and matcher", func(t *testing.T) {
+		result, err := c.CompareBody(
+			map[string]interface{}{"id": "123", "name": "Jane"},
+			map[string]interface{}{"id": "abc"},
+			map[string]contract.MatcherSet{
+				"$.id": {Matchers: []contract.Matcher{{Match: "regex", Regex: `\d+`}}},
+			},
+		)
+		require.NoError(t, err)
+		require.Len(t, result.Mismatches, 2)
+
+		assert.Equal(t, matcher.Mismatch{
+			Kind:     matcher.KindBody,
+			Path:     "$.id",
+			Expected: "123",
+			Actual:   "abc",
+			Matcher:  "regex",
+			Message:  result.Mismatches[0].Message,
+		}, result.Mismatches[0])
+		assert.Equal(t, "$.name", result.Mismatches[1].Path)
+		assert.Equal(t, "missing field", result.Mismatches[1].Message)
+		assert.Equal(t, matcher.JoinMismatches(result.Mismatches), result.Diff)
+	})
+
+	t.Run("header and status mismatches", func(t *testing.T) {
+		result, err := c.CompareHeaders(
+			map[string]interface{}{"Accept": "application/json"},
+			map[string]interface{}{},
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, result.Mismatches, 1)
+		assert.Equal(t, matcher.KindHeader, result.Mismatches[0].Kind)
+		assert.Equal(t, "Accept", result.Mismatches[0].Path)
+
+		result, err = c.CompareStatus(200, 500, nil)
+		require.NoError(t, err)
+		require.Len(t, result.Mismatches, 1)
+		assert.Equal(t, matcher.KindStatus, result.Mismatches[0].Kind)
+		assert.Equal(t, 200, result.Mismatches[0].Expected)
+		assert.Equal(t, 500, result.Mismatches[0].Actual)
+	})
+}
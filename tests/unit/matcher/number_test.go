@@ -0,0 +1,113 @@
+package matcher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestNumberMatcher_Match(t *testing.T) {
+	m := matcher.NewNumberMatcher()
+	assert.Equal(t, "number", m.Name())
+
+	for _, actual := range []interface{}{float64(1), float64(1.5), json.Number("-3e2"), 7} {
+		result, err := m.Match(float64(0), actual)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, "%v", actual)
+	}
+
+	for _, actual := range []interface{}{"1", nil, true, []interface{}{}} {
+		result, err := m.Match(float64(0), actual)
+		require.NoError(t, err)
+		assert.False(t, result.Matched, "%v", actual)
+		assert.Contains(t, result.Diff, "expected a number")
+	}
+}
+
+func TestIntegerMatcher_Match(t *testing.T) {
+	m := matcher.NewIntegerMatcher()
+	assert.Equal(t, "integer", m.Name())
+
+	t.Run("integer tokens match", func(t *testing.T) {
+		for _, actual := range []interface{}{json.Number("100"), json.Number("-5"), float64(42), 3} {
+			result, err := m.Match(float64(0), actual)
+			require.NoError(t, err)
+			assert.True(t, result.Matched, "%v", actual)
+		}
+	})
+
+	t.Run("decimal tokens do not match", func(t *testing.T) {
+		for _, actual := range []interface{}{json.Number("100.0"), json.Number("1e2"), float64(1.5), "100"} {
+			result, err := m.Match(float64(0), actual)
+			require.NoError(t, err)
+			assert.False(t, result.Matched, "%v", actual)
+			assert.Contains(t, result.Diff, "expected an integer")
+		}
+	})
+}
+
+func TestDecimalMatcher_Match(t *testing.T) {
+	m := matcher.NewDecimalMatcher()
+	assert.Equal(t, "decimal", m.Name())
+
+	t.Run("decimal tokens match", func(t *testing.T) {
+		for _, actual := range []interface{}{json.Number("100.0"), json.Number("0.25"), float64(1.5)} {
+			result, err := m.Match(float64(0), actual)
+			require.NoError(t, err)
+			assert.True(t, result.Matched, "%v", actual)
+		}
+	})
+
+	t.Run("integer tokens do not match", func(t *testing.T) {
+		for _, actual := range []interface{}{json.Number("100"), float64(2), "1.5"} {
+			result, err := m.Match(float64(0), actual)
+			require.NoError(t, err)
+			assert.False(t, result.Matched, "%v", actual)
+			assert.Contains(t, result.Diff, "expected a decimal")
+		}
+	})
+}
+
+func TestComparator_NumberRules(t *testing.T) {
+	t.Run("registers numeric matchers", func(t *testing.T) {
+		c := matcher.NewComparator()
+		for _, name := range []string{"number", "integer", "decimal"} {
+			_, ok := c.GetMatcher(name)
+			assert.True(t, ok, name)
+		}
+	})
+
+	t.Run("integer rule checks the raw JSON token", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.amount": ruleSet("integer"),
+		}
+		expected := map[string]interface{}{"amount": float64(100)}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{"amount": json.Number("2500")}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{"amount": json.Number("25.00")}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.amount")
+	})
+
+	t.Run("equality treats json.Number like float64", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"id": float64(1)},
+			map[string]interface{}{"id": json.Number("1")},
+			nil,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+}
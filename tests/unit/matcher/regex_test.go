@@ -0,0 +1,165 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func regexRule(re string) contract.Matcher {
+	return contract.Matcher{Match: "regex", Regex: re}
+}
+
+func TestRegexMatcher_MatchRule(t *testing.T) {
+	t.Run("returns correct name", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+		assert.Equal(t, "regex", m.Name())
+	})
+
+	t.Run("matching string passes", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		result, err := m.MatchRule(regexRule(`[a-f0-9]{8}`), "00000000", "deadbeef")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("regex must match the whole value", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		result, err := m.MatchRule(regexRule(`\d+`), "1", "id-42")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, `"id-42"`)
+	})
+
+	t.Run("numbers are matched by their string form", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		result, err := m.MatchRule(regexRule(`\d+`), float64(1), float64(42))
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("null does not match", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		result, err := m.MatchRule(regexRule(`.*`), "a", nil)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+
+	t.Run("invalid regex returns error", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		_, err := m.MatchRule(regexRule(`(`), "a", "a")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid regex")
+	})
+
+	t.Run("missing regex returns error", func(t *testing.T) {
+		m := matcher.NewRegexMatcher()
+
+		_, err := m.MatchRule(contract.Matcher{Match: "regex"}, "a", "a")
+		require.Error(t, err)
+
+		_, err = m.Match("a", "a")
+		require.Error(t, err)
+	})
+}
+
+func TestComparator_RegexRules(t *testing.T) {
+	t.Run("applies regex to body values", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.id": {Matchers: []contract.Matcher{regexRule(`[0-9a-f-]{36}`)}},
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"id": "00000000-0000-0000-0000-000000000000"},
+			map[string]interface{}{"id": "3f1c2b9e-1a2b-4c3d-8e9f-0a1b2c3d4e5f"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("applies regex to headers ignoring header name case", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"location": {Matchers: []contract.Matcher{regexRule(`/orders/\d+`)}},
+		}
+
+		result, err := c.CompareHeaders(
+			map[string]interface{}{"Location": "/orders/1"},
+			map[string]interface{}{"Location": "/orders/981"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("applies regex to every query value", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"id": {Matchers: []contract.Matcher{regexRule(`\d+`)}},
+		}
+		expected := map[string][]string{"id": {"1"}}
+
+		result, err := c.CompareQuery(expected, map[string][]string{"id": {"7", "8"}}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareQuery(expected, map[string][]string{"id": {"7", "x"}}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "query id")
+	})
+
+	t.Run("query without rule requires exact values", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareQuery(
+			map[string][]string{"status": {"active"}},
+			map[string][]string{"status": {"inactive"}},
+			nil,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+
+		result, err = c.CompareQuery(
+			map[string][]string{"status": {"active"}},
+			map[string][]string{},
+			nil,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "query status: missing query parameter")
+	})
+
+	t.Run("applies regex to path", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rule := contract.MatcherSet{Matchers: []contract.Matcher{regexRule(`/users/\d+`)}}
+
+		result, err := c.ComparePath("/users/1", "/users/99", rule)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.ComparePath("/users/1", "/users/abc", rule)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "path")
+	})
+
+	t.Run("path without rule uses equality", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.ComparePath("/users/1", "/users/2", contract.MatcherSet{})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+}
@@ -0,0 +1,252 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestTypeMatcher_Match(t *testing.T) {
+	t.Run("returns correct name", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+		assert.Equal(t, "type", m.Name())
+	})
+
+	t.Run("same type with different values matches", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+
+		cases := []struct {
+			name     string
+			expected interface{}
+			actual   interface{}
+		}{
+			{"string", "John", "Jane"},
+			{"number", float64(1), float64(42.5)},
+			{"boolean", true, false},
+			{"null", nil, nil},
+			{"object", map[string]interface{}{"a": "b"}, map[string]interface{}{}},
+			{"array", []interface{}{"a"}, []interface{}{"b", "c"}},
+		}
+		for _, tc := range cases {
+			result, err := m.Match(tc.expected, tc.actual)
+			require.NoError(t, err, tc.name)
+			assert.True(t, result.Matched, tc.name)
+		}
+	})
+
+	t.Run("different types do not match", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+
+		result, err := m.Match("1", float64(1))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "expected type string, got number")
+	})
+
+	t.Run("null does not match a string", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+
+		result, err := m.Match("John", nil)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+}
+
+func TestTypeMatcher_MatchRule(t *testing.T) {
+	t.Run("array within min and max matches", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+		rule := contract.Matcher{Match: "type", Min: intPtr(1), Max: intPtr(3)}
+
+		result, err := m.MatchRule(rule, []interface{}{"a"}, []interface{}{"a", "b", "c"})
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("array shorter than min fails", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+		rule := contract.Matcher{Match: "type", Min: intPtr(2)}
+
+		result, err := m.MatchRule(rule, []interface{}{"a"}, []interface{}{"a"})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "at least 2 elements, got 1")
+	})
+
+	t.Run("array longer than max fails", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+		rule := contract.Matcher{Match: "type", Max: intPtr(1)}
+
+		result, err := m.MatchRule(rule, []interface{}{"a"}, []interface{}{"a", "b"})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "at most 1 elements, got 2")
+	})
+
+	t.Run("type mismatch is reported before bounds", func(t *testing.T) {
+		m := matcher.NewTypeMatcher()
+		rule := contract.Matcher{Match: "type", Min: intPtr(1)}
+
+		result, err := m.MatchRule(rule, []interface{}{"a"}, "a")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "expected type array")
+	})
+}
+
+func TestComparator_CompareBody_TypeRules(t *testing.T) {
+	t.Run("type rule ignores values", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.name": ruleSet("type"),
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"name": "John"},
+			map[string]interface{}{"name": "Jane"},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(
+			map[string]interface{}{"name": "John"},
+			map[string]interface{}{"name": float64(1)},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.name")
+	})
+
+	t.Run("eachLike validates every element against the first example", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.items": {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+		}
+		expected := map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(1), "name": "a"},
+			},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(7), "name": "x"},
+				map[string]interface{}{"id": float64(8), "name": "y"},
+				map[string]interface{}{"id": float64(9), "name": "z"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(7), "name": "x"},
+				map[string]interface{}{"id": "8", "name": "y"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.items[1].id")
+	})
+
+	t.Run("eachLike enforces min", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.items": {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+		}
+
+		result, err := c.CompareBody(
+			map[string]interface{}{"items": []interface{}{"a"}},
+			map[string]interface{}{"items": []interface{}{}},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.items: expected array with at least 1 elements")
+	})
+
+	t.Run("element rules still apply inside eachLike", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.items":         {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+			"$.items[*].kind": ruleSet("equality"),
+		}
+		expected := map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(1), "kind": "book"},
+			},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"id": float64(2), "kind": "book"},
+				map[string]interface{}{"id": float64(3), "kind": "dvd"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.items[1].kind")
+		assert.NotContains(t, result.Diff, "$.items[1].id")
+	})
+
+	t.Run("bounds do not cascade to nested arrays", func(t *testing.T) {
+		c := matcher.NewComparator()
+		expected := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "Jane", "tags": []interface{}{"admin"}},
+			},
+		}
+		actual := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "John", "tags": []interface{}{}},
+				map[string]interface{}{"name": "Mary", "tags": []interface{}{"a", "b", "c"}},
+			},
+		}
+
+		result, err := c.CompareBody(expected, actual, map[string]contract.MatcherSet{
+			"$.users": {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1), Max: intPtr(2)}}},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		// Nested arrays are still type checked
+		actual["users"].([]interface{})[0].(map[string]interface{})["tags"] = "admin"
+		result, err = c.CompareBody(expected, actual, map[string]contract.MatcherSet{
+			"$.users": {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.users[0].tags")
+	})
+
+	t.Run("bounds of a nested rule apply to the nested array", func(t *testing.T) {
+		c := matcher.NewComparator()
+		expected := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"tags": []interface{}{"admin"}},
+			},
+		}
+		actual := map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"tags": []interface{}{}},
+			},
+		}
+
+		result, err := c.CompareBody(expected, actual, map[string]contract.MatcherSet{
+			"$.users":         {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+			"$.users[*].tags": {Matchers: []contract.Matcher{{Match: "type", Min: intPtr(1)}}},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.users[0].tags: expected array with at least 1 elements, got 0")
+	})
+}
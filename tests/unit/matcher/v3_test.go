@@ -0,0 +1,150 @@
+package matcher_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestIncludeMatcher_MatchRule(t *testing.T) {
+	m := matcher.NewIncludeMatcher()
+	assert.Equal(t, "include", m.Name())
+
+	t.Run("string containing value matches", func(t *testing.T) {
+		rule := contract.Matcher{Match: "include", Value: "world"}
+		result, err := m.MatchRule(rule, "hello world", "goodbye world!")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("string without value fails", func(t *testing.T) {
+		rule := contract.Matcher{Match: "include", Value: "world"}
+		result, err := m.MatchRule(rule, "hello world", "hello there")
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, `to include "world"`)
+	})
+
+	t.Run("falls back to expected value", func(t *testing.T) {
+		result, err := m.Match("abc", "xxabcxx")
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+
+	t.Run("non-string actual fails", func(t *testing.T) {
+		result, err := m.Match("1", float64(1))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+
+	t.Run("non-string value returns error", func(t *testing.T) {
+		_, err := m.MatchRule(contract.Matcher{Match: "include", Value: float64(1)}, "a", "a")
+		require.Error(t, err)
+	})
+}
+
+func TestNullMatcher_Match(t *testing.T) {
+	m := matcher.NewNullMatcher()
+	assert.Equal(t, "null", m.Name())
+
+	result, err := m.Match(nil, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+
+	result, err = m.Match(nil, "")
+	require.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.Contains(t, result.Diff, "expected null")
+}
+
+func TestBooleanMatcher_Match(t *testing.T) {
+	m := matcher.NewBooleanMatcher()
+	assert.Equal(t, "boolean", m.Name())
+
+	result, err := m.Match(true, false)
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+
+	result, err = m.Match(true, "true")
+	require.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.Contains(t, result.Diff, "expected a boolean")
+}
+
+func TestComparator_EqualityOverride(t *testing.T) {
+	t.Run("equality re-tightens a field under a type rule", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$":        ruleSet("type"),
+			"$.status": ruleSet("equality"),
+		}
+		expected := map[string]interface{}{"id": float64(1), "status": "active"}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{"id": float64(9), "status": "active"}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{"id": float64(9), "status": "deleted"}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.status")
+	})
+
+	t.Run("equality re-tightens a whole subtree", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$":        ruleSet("type"),
+			"$.config": ruleSet("equality"),
+		}
+		expected := map[string]interface{}{
+			"name":   "svc",
+			"config": map[string]interface{}{"mode": "fast", "levels": []interface{}{"a", "b"}},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"name":   "other",
+			"config": map[string]interface{}{"mode": "fast", "levels": []interface{}{"a", "b"}},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"name":   "other",
+			"config": map[string]interface{}{"mode": "slow", "levels": []interface{}{"a"}, "debug": true},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.config.mode")
+		assert.Contains(t, result.Diff, "$.config.levels")
+		assert.Contains(t, result.Diff, "$.config.debug: unexpected field")
+	})
+
+	t.Run("v3 matchers in a body", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.message":   {Matchers: []contract.Matcher{{Match: "include", Value: "created"}}},
+			"$.deletedAt": ruleSet("null"),
+			"$.active":    ruleSet("boolean"),
+		}
+		expected := map[string]interface{}{"message": "user created", "deletedAt": nil, "active": true}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"message": "order 12 created at noon", "deletedAt": nil, "active": false,
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"message": "order deleted", "deletedAt": "2024-01-01", "active": "yes",
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.message")
+		assert.Contains(t, result.Diff, "$.deletedAt")
+		assert.Contains(t, result.Diff, "$.active")
+	})
+}
@@ -0,0 +1,235 @@
+package matcher_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jt-chihara/yakusoku/internal/contract"
+	"github.com/jt-chihara/yakusoku/internal/matcher"
+)
+
+func TestSemverMatcher_Match(t *testing.T) {
+	m := matcher.NewSemverMatcher()
+	assert.Equal(t, "semver", m.Name())
+
+	for _, v := range []string{"1.0.0", "10.2.33", "1.0.0-alpha.1", "2.1.0+build.5"} {
+		result, err := m.Match("1.0.0", v)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, v)
+	}
+	for _, v := range []interface{}{"1.0", "v1.0.0", "01.0.0", float64(1)} {
+		result, err := m.Match("1.0.0", v)
+		require.NoError(t, err)
+		assert.False(t, result.Matched, "%v", v)
+	}
+}
+
+func TestNotEmptyMatcher_Match(t *testing.T) {
+	m := matcher.NewNotEmptyMatcher()
+	assert.Equal(t, "notEmpty", m.Name())
+
+	t.Run("non-empty values of the expected type match", func(t *testing.T) {
+		cases := [][2]interface{}{
+			{"a", "b"},
+			{[]interface{}{"a"}, []interface{}{"x", "y"}},
+			{map[string]interface{}{"a": "b"}, map[string]interface{}{"c": "d"}},
+			{float64(1), float64(0)},
+		}
+		for _, tc := range cases {
+			result, err := m.Match(tc[0], tc[1])
+			require.NoError(t, err)
+			assert.True(t, result.Matched, "%v", tc[1])
+		}
+	})
+
+	t.Run("empty values fail", func(t *testing.T) {
+		cases := [][2]interface{}{
+			{"a", ""},
+			{[]interface{}{"a"}, []interface{}{}},
+			{map[string]interface{}{"a": "b"}, map[string]interface{}{}},
+			{nil, nil},
+		}
+		for _, tc := range cases {
+			result, err := m.Match(tc[0], tc[1])
+			require.NoError(t, err)
+			assert.False(t, result.Matched, "%v", tc[1])
+		}
+	})
+
+	t.Run("different type fails", func(t *testing.T) {
+		result, err := m.Match("a", float64(1))
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+}
+
+func TestStatusCodeMatcher_MatchRule(t *testing.T) {
+	m := matcher.NewStatusCodeMatcher()
+	assert.Equal(t, "statusCode", m.Name())
+
+	t.Run("named classes", func(t *testing.T) {
+		cases := []struct {
+			class string
+			code  int
+			match bool
+		}{
+			{"information", 101, true},
+			{"success", 201, true},
+			{"success", 301, false},
+			{"redirect", 302, true},
+			{"clientError", 404, true},
+			{"clientError", 500, false},
+			{"serverError", 503, true},
+			{"nonError", 302, true},
+			{"nonError", 400, false},
+			{"error", 418, true},
+		}
+		for _, tc := range cases {
+			result, err := m.MatchRule(contract.Matcher{Match: "statusCode", Status: tc.class}, 0, tc.code)
+			require.NoError(t, err)
+			assert.Equal(t, tc.match, result.Matched, "%s %d", tc.class, tc.code)
+		}
+	})
+
+	t.Run("explicit list of codes", func(t *testing.T) {
+		rule := contract.Matcher{Match: "statusCode", Status: []interface{}{float64(200), float64(202)}}
+
+		result, err := m.MatchRule(rule, 200, 202)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+
+		result, err = m.MatchRule(rule, 200, 201)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+	})
+
+	t.Run("unknown class returns error", func(t *testing.T) {
+		_, err := m.MatchRule(contract.Matcher{Match: "statusCode", Status: "great"}, 200, 200)
+		require.Error(t, err)
+	})
+
+	t.Run("rules parse from v4 JSON", func(t *testing.T) {
+		var rules contract.MatchingRules
+		err := json.Unmarshal([]byte(`{"status":{"matchers":[{"match":"statusCode","status":[200,204]}]}}`), &rules)
+		require.NoError(t, err)
+
+		c := matcher.NewComparator()
+		result, err := c.CompareStatus(200, 204, rules.Status)
+		require.NoError(t, err)
+		assert.True(t, result.Matched)
+	})
+}
+
+func TestComparator_ArrayContains(t *testing.T) {
+	rules := map[string]contract.MatcherSet{
+		"$.events": {Matchers: []contract.Matcher{{
+			Match: "arrayContains",
+			Variants: []contract.Variant{
+				{Index: 0, Rules: map[string]contract.MatcherSet{
+					"$.id": ruleSet("integer"),
+				}},
+				{Index: 1},
+			},
+		}}},
+	}
+	expected := map[string]interface{}{
+		"events": []interface{}{
+			map[string]interface{}{"type": "created", "id": float64(1)},
+			map[string]interface{}{"type": "shipped"},
+		},
+	}
+
+	t.Run("matches variants in any order with extra elements", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"events": []interface{}{
+				map[string]interface{}{"type": "paid"},
+				map[string]interface{}{"type": "shipped", "carrier": "ups"},
+				map[string]interface{}{"type": "created", "id": json.Number("77")},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+	})
+
+	t.Run("fails when a variant has no matching element", func(t *testing.T) {
+		c := matcher.NewComparator()
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"events": []interface{}{
+				map[string]interface{}{"type": "created", "id": json.Number("7.5")},
+				map[string]interface{}{"type": "shipped"},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, "$.events: no element matches variant 0")
+	})
+}
+
+func TestComparator_EachKeyEachValue(t *testing.T) {
+	t.Run("eachKey checks every key", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.prices": {Matchers: []contract.Matcher{{
+				Match: "eachKey",
+				Rules: []contract.Matcher{{Match: "regex", Regex: `[A-Z]{3}`}},
+			}}},
+		}
+		expected := map[string]interface{}{"prices": map[string]interface{}{"USD": float64(1)}}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"prices": map[string]interface{}{"EUR": float64(2), "JPY": float64(300)},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"prices": map[string]interface{}{"eur": float64(2)},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, `key "eur"`)
+	})
+
+	t.Run("eachValue checks every value against the example", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{
+			"$.stock": {Matchers: []contract.Matcher{{
+				Match: "eachValue",
+				Rules: []contract.Matcher{{Match: "type"}},
+			}}},
+		}
+		expected := map[string]interface{}{
+			"stock": map[string]interface{}{"sku-1": map[string]interface{}{"count": float64(1)}},
+		}
+
+		result, err := c.CompareBody(expected, map[string]interface{}{
+			"stock": map[string]interface{}{
+				"sku-9": map[string]interface{}{"count": float64(4)},
+				"sku-8": map[string]interface{}{"count": float64(0)},
+			},
+		}, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Matched, result.Diff)
+
+		result, err = c.CompareBody(expected, map[string]interface{}{
+			"stock": map[string]interface{}{"sku-9": map[string]interface{}{"count": "four"}},
+		}, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Matched)
+		assert.Contains(t, result.Diff, `value ["sku-9"]`)
+	})
+
+	t.Run("eachKey without rules returns error", func(t *testing.T) {
+		c := matcher.NewComparator()
+		rules := map[string]contract.MatcherSet{"$": ruleSet("eachKey")}
+
+		_, err := c.CompareBody(map[string]interface{}{}, map[string]interface{}{}, rules)
+		require.Error(t, err)
+	})
+}
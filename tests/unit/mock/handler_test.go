@@ -126,6 +126,74 @@ func TestHandler_MatchRequest(t *testing.T) {
 	})
 }
 
+func TestHandler_RequestBodyContentType(t *testing.T) {
+	t.Run("matches form bodies by field", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "login",
+			Request: contract.Request{
+				Method:  "POST",
+				Path:    "/login",
+				Headers: map[string]interface{}{"Content-Type": "application/x-www-form-urlencoded"},
+				Body:    map[string]interface{}{"user": "a", "pass": "b", "scope": []interface{}{"read", "write"}, "ttl": float64(3600)},
+			},
+			Response: contract.Response{Status: 200},
+		})
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewBufferString("pass=b&scope=read&scope=write&ttl=3600&user=a"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, w.Body.String())
+
+		req = httptest.NewRequest("POST", "/login", bytes.NewBufferString("pass=c&scope=read&scope=write&ttl=3600&user=a"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("matches encoded form strings regardless of field order", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "login",
+			Request: contract.Request{
+				Method:  "POST",
+				Path:    "/login",
+				Headers: map[string]interface{}{"Content-Type": "application/x-www-form-urlencoded"},
+				Body:    "user=a&pass=b",
+			},
+			Response: contract.Response{Status: 200},
+		})
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewBufferString("pass=b&user=a"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code, w.Body.String())
+	})
+
+	t.Run("keeps text bodies as strings", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "post note",
+			Request: contract.Request{
+				Method:  "POST",
+				Path:    "/notes",
+				Headers: map[string]interface{}{"Content-Type": "text/plain"},
+				Body:    "123",
+			},
+			Response: contract.Response{Status: 201},
+		})
+
+		req := httptest.NewRequest("POST", "/notes", bytes.NewBufferString("123"))
+		req.Header.Set("Content-Type", "text/plain")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, 201, w.Code, w.Body.String())
+	})
+}
+
 func TestHandler_ResponseBody(t *testing.T) {
 	t.Run("returns JSON body", func(t *testing.T) {
 		handler := mock.NewHandler()
@@ -262,3 +330,88 @@ func TestHandler_UnmatchedRequest(t *testing.T) {
 		assert.Contains(t, string(body), "no matching interaction")
 	})
 }
+
+func TestHandler_MatchingRules(t *testing.T) {
+	t.Run("matches path by regex", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "get user",
+			Request: contract.Request{
+				Method: "GET",
+				Path:   "/users/1",
+				MatchingRules: contract.MatchingRules{
+					Path: contract.MatcherSet{Matchers: []contract.Matcher{{Match: "regex", Regex: `/users/\d+`}}},
+				},
+			},
+			Response: contract.Response{Status: 200},
+		})
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/users/42", nil))
+		assert.Equal(t, 200, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/users/abc", nil))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("matches query and headers by regex", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "search orders",
+			Request: contract.Request{
+				Method:  "GET",
+				Path:    "/orders",
+				Query:   map[string][]string{"since": {"2024-01-01"}},
+				Headers: map[string]interface{}{"If-None-Match": `"abc"`},
+				MatchingRules: contract.MatchingRules{
+					Query: map[string]contract.MatcherSet{
+						"since": {Matchers: []contract.Matcher{{Match: "regex", Regex: `\d{4}-\d{2}-\d{2}`}}},
+					},
+					Headers: map[string]contract.MatcherSet{
+						"If-None-Match": {Matchers: []contract.Matcher{{Match: "regex", Regex: `"\w+"`}}},
+					},
+				},
+			},
+			Response: contract.Response{Status: 200},
+		})
+
+		req := httptest.NewRequest("GET", "/orders?since=2025-06-30", nil)
+		req.Header.Set("If-None-Match", `"xyz123"`)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+
+		req = httptest.NewRequest("GET", "/orders?since=yesterday", nil)
+		req.Header.Set("If-None-Match", `"xyz123"`)
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("matches body values by regex", func(t *testing.T) {
+		handler := mock.NewHandler()
+		handler.RegisterInteraction(&contract.Interaction{
+			Description: "create order",
+			Request: contract.Request{
+				Method: "POST",
+				Path:   "/orders",
+				Body:   map[string]interface{}{"sku": "ABC-1", "quantity": 1},
+				MatchingRules: contract.MatchingRules{
+					Body: map[string]contract.MatcherSet{
+						"$.sku": {Matchers: []contract.Matcher{{Match: "regex", Regex: `[A-Z]+-\d+`}}},
+					},
+				},
+			},
+			Response: contract.Response{Status: 201},
+		})
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/orders", bytes.NewBufferString(`{"sku":"XYZ-99","quantity":1}`)))
+		assert.Equal(t, 201, w.Code)
+
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/orders", bytes.NewBufferString(`{"sku":"xyz","quantity":1}`)))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
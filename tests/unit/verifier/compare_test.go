@@ -6,31 +6,54 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/jt-chihara/yakusoku/internal/contract"
 	"github.com/jt-chihara/yakusoku/internal/verifier"
 )
 
 func TestCompare_Status(t *testing.T) {
 	t.Run("matching status codes", func(t *testing.T) {
 		cmp := verifier.NewComparer()
-		result := cmp.CompareStatus(200, 200)
+		result, err := cmp.CompareStatus(200, 200, nil)
+		require.NoError(t, err)
 		assert.True(t, result.Match)
 	})
 
 	t.Run("different status codes", func(t *testing.T) {
 		cmp := verifier.NewComparer()
-		result := cmp.CompareStatus(200, 404)
+		result, err := cmp.CompareStatus(200, 404, nil)
+		require.NoError(t, err)
 		assert.False(t, result.Match)
-		assert.Contains(t, result.Diff, "expected status 200")
+		assert.Contains(t, result.Diff, "status: expected 200, got 404")
 		assert.Contains(t, result.Diff, "got 404")
 	})
 }
 
+func TestCompare_StatusWithRules(t *testing.T) {
+	t.Run("status class matches any code in class", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		rule := &contract.MatcherSet{Matchers: []contract.Matcher{{Match: "statusCode", Status: "success"}}}
+		result, err := cmp.CompareStatus(200, 204, rule)
+		require.NoError(t, err)
+		assert.True(t, result.Match)
+	})
+
+	t.Run("status class rejects code outside class", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		rule := &contract.MatcherSet{Matchers: []contract.Matcher{{Match: "statusCode", Status: "success"}}}
+		result, err := cmp.CompareStatus(200, 404, rule)
+		require.NoError(t, err)
+		assert.False(t, result.Match)
+		assert.Contains(t, result.Diff, "expected a success status, got 404")
+	})
+}
+
 func TestCompare_Headers(t *testing.T) {
 	t.Run("matching headers", func(t *testing.T) {
 		cmp := verifier.NewComparer()
 		expected := map[string]interface{}{"Content-Type": "application/json"}
 		actual := map[string]string{"Content-Type": "application/json"}
-		result := cmp.CompareHeaders(expected, actual)
+		result, err := cmp.CompareHeaders(expected, actual, nil)
+		require.NoError(t, err)
 		assert.True(t, result.Match)
 	})
 
@@ -38,7 +61,8 @@ func TestCompare_Headers(t *testing.T) {
 		cmp := verifier.NewComparer()
 		expected := map[string]interface{}{"Content-Type": "application/json"}
 		actual := map[string]string{}
-		result := cmp.CompareHeaders(expected, actual)
+		result, err := cmp.CompareHeaders(expected, actual, nil)
+		require.NoError(t, err)
 		assert.False(t, result.Match)
 		assert.Contains(t, result.Diff, "Content-Type")
 	})
@@ -47,7 +71,8 @@ func TestCompare_Headers(t *testing.T) {
 		cmp := verifier.NewComparer()
 		expected := map[string]interface{}{"Content-Type": "application/json"}
 		actual := map[string]string{"Content-Type": "text/plain"}
-		result := cmp.CompareHeaders(expected, actual)
+		result, err := cmp.CompareHeaders(expected, actual, nil)
+		require.NoError(t, err)
 		assert.False(t, result.Match)
 	})
 
@@ -58,16 +83,45 @@ func TestCompare_Headers(t *testing.T) {
 			"Content-Type": "application/json",
 			"X-Extra":      "ignored",
 		}
-		result := cmp.CompareHeaders(expected, actual)
+		result, err := cmp.CompareHeaders(expected, actual, nil)
+		require.NoError(t, err)
 		assert.True(t, result.Match)
 	})
 
 	t.Run("nil expected headers match any actual", func(t *testing.T) {
 		cmp := verifier.NewComparer()
 		actual := map[string]string{"Content-Type": "application/json"}
-		result := cmp.CompareHeaders(nil, actual)
+		result, err := cmp.CompareHeaders(nil, actual, nil)
+		require.NoError(t, err)
+		assert.True(t, result.Match)
+	})
+}
+
+func TestCompare_HeadersWithRules(t *testing.T) {
+	t.Run("regex rule matches varying header value", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		expected := map[string]interface{}{"Location": "/users/1"}
+		actual := map[string]string{"Location": "/users/42"}
+		rules := map[string]contract.MatcherSet{
+			"Location": {Matchers: []contract.Matcher{{Match: "regex", Regex: `/users/\d+`}}},
+		}
+		result, err := cmp.CompareHeaders(expected, actual, rules)
+		require.NoError(t, err)
 		assert.True(t, result.Match)
 	})
+
+	t.Run("regex rule rejects non matching header value", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		expected := map[string]interface{}{"ETag": `"abc"`}
+		actual := map[string]string{"ETag": "W/abc"}
+		rules := map[string]contract.MatcherSet{
+			"ETag": {Matchers: []contract.Matcher{{Match: "regex", Regex: `"[a-z0-9]+"`}}},
+		}
+		result, err := cmp.CompareHeaders(expected, actual, rules)
+		require.NoError(t, err)
+		assert.False(t, result.Match)
+		assert.Contains(t, result.Diff, "header ETag")
+	})
 }
 
 func TestCompare_Body(t *testing.T) {
@@ -130,4 +184,84 @@ func TestCompare_Body(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, result.Match)
 	})
+
+	t.Run("variable length arrays pass with type rule", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		minLen := 1
+		rules := map[string]contract.MatcherSet{
+			"$": {Matchers: []contract.Matcher{{Match: "type", Min: &minLen}}},
+		}
+		expected := []interface{}{
+			map[string]interface{}{"id": float64(1)},
+		}
+		actual := []interface{}{
+			map[string]interface{}{"id": float64(5)},
+			map[string]interface{}{"id": float64(6)},
+		}
+		result, err := cmp.CompareBody(expected, actual, rules)
+		require.NoError(t, err)
+		assert.True(t, result.Match)
+	})
+}
+
+func TestCompare_Query(t *testing.T) {
+	t.Run("regex rule matches any value", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		rules := map[string]contract.MatcherSet{
+			"page": {Matchers: []contract.Matcher{{Match: "regex", Regex: `\d+`}}},
+		}
+		result, err := cmp.CompareQuery(
+			map[string][]string{"page": {"1"}},
+			map[string][]string{"page": {"7"}},
+			rules,
+		)
+		require.NoError(t, err)
+		assert.True(t, result.Match)
+	})
+
+	t.Run("values without rule must be equal", func(t *testing.T) {
+		cmp := verifier.NewComparer()
+		result, err := cmp.CompareQuery(
+			map[string][]string{"page": {"1"}},
+			map[string][]string{"page": {"7"}},
+			nil,
+		)
+		require.NoError(t, err)
+		assert.False(t, result.Match)
+		assert.Contains(t, result.Diff, "query page")
+	})
+}
+
+func TestCompare_RegisteredMatchers(t *testing.T) {
+	cmp := verifier.NewComparer()
+	expected := map[string]interface{}{
+		"createdOn": "2024-01-31",
+		"version":   "1.0.0",
+		"tags":      []interface{}{"a"},
+	}
+	rules := map[string]contract.MatcherSet{
+		"$.createdOn": {Matchers: []contract.Matcher{{Match: "date", Format: "yyyy-MM-dd"}}},
+		"$.version":   {Matchers: []contract.Matcher{{Match: "semver"}}},
+		"$.tags":      {Matchers: []contract.Matcher{{Match: "notEmpty"}}},
+	}
+
+	result, err := cmp.CompareBody(expected, map[string]interface{}{
+		"createdOn": "2025-12-01",
+		"version":   "2.3.4",
+		"tags":      []interface{}{"x", "y"},
+	}, rules)
+	require.NoError(t, err)
+	assert.True(t, result.Match, result.Diff)
+
+	result, err = cmp.CompareBody(expected, map[string]interface{}{
+		"createdOn": "01/12/2025",
+		"version":   "2.3",
+		"tags":      []interface{}{},
+	}, rules)
+	require.NoError(t, err)
+	assert.False(t, result.Match)
+	require.Len(t, result.Mismatches, 3)
+	assert.Equal(t, "date", result.Mismatches[0].Matcher)
+	assert.Equal(t, "notEmpty", result.Mismatches[1].Matcher)
+	assert.Equal(t, "semver", result.Mismatches[2].Matcher)
 }
@@ -2,9 +2,11 @@ package verifier_test
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -96,3 +98,176 @@ func TestProviderStates_Setup(t *testing.T) {
 		require.NoError(t, err) // Should not error, just skip
 	})
 }
+
+func TestProviderStates_Actions(t *testing.T) {
+	t.Run("sends setup and teardown actions in the body", func(t *testing.T) {
+		var received []map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			received = append(received, body)
+		}))
+		defer server.Close()
+
+		ps := verifier.NewProviderStates(server.URL)
+		require.NoError(t, ps.Setup("user exists", map[string]interface{}{"id": float64(1)}))
+		require.NoError(t, ps.Teardown("user exists", map[string]interface{}{"id": float64(1)}))
+
+		require.Len(t, received, 2)
+		assert.Equal(t, "setup", received[0]["action"])
+		assert.Equal(t, "teardown", received[1]["action"])
+		assert.Equal(t, "user exists", received[1]["state"])
+	})
+
+	t.Run("sends state changes as query parameters", func(t *testing.T) {
+		var received url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.URL.Query()
+		}))
+		defer server.Close()
+
+		ps := verifier.NewProviderStates(server.URL + "/states?token=abc")
+		ps.SetAsQuery(true)
+		require.NoError(t, ps.Setup("user exists", map[string]interface{}{"name": "Jane", "id": float64(1)}))
+
+		assert.Equal(t, "user exists", received.Get("state"))
+		assert.Equal(t, "setup", received.Get("action"))
+		assert.Equal(t, "Jane", received.Get("name"))
+		assert.Equal(t, "1", received.Get("id"))
+		assert.Equal(t, "abc", received.Get("token"))
+	})
+
+	t.Run("tears down multiple states in reverse order", func(t *testing.T) {
+		var received []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			received = append(received, body["state"].(string))
+			if body["state"] == "b" {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		defer server.Close()
+
+		ps := verifier.NewProviderStates(server.URL)
+		err := ps.TeardownMultiple([]contract.ProviderState{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "teardown failed with status 500")
+		assert.Equal(t, []string{"c", "b", "a"}, received)
+	})
+}
+
+func TestProviderStates_SetupWithValues(t *testing.T) {
+	t.Run("returns the values of a JSON object response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id": 42, "name": "Jane"}`))
+		}))
+		defer server.Close()
+
+		values, err := verifier.NewProviderStates(server.URL).SetupWithValues("user exists", nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"id": float64(42), "name": "Jane"}, values)
+	})
+
+	t.Run("ignores responses that are not objects", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		values, err := verifier.NewProviderStates(server.URL).SetupWithValues("user exists", nil)
+		require.NoError(t, err)
+		assert.Nil(t, values)
+	})
+}
+
+func TestProviderStates_Handlers(t *testing.T) {
+	t.Run("calls setup and teardown handlers", func(t *testing.T) {
+		var calls []string
+		ps := verifier.NewProviderStates("")
+		ps.SetHandlers(
+			map[string]verifier.StateHandler{
+				"user exists": func(params map[string]interface{}) (map[string]interface{}, error) {
+					calls = append(calls, "setup")
+					return map[string]interface{}{"id": params["id"]}, nil
+				},
+			},
+			map[string]verifier.StateHandler{
+				"user exists": func(params map[string]interface{}) (map[string]interface{}, error) {
+					calls = append(calls, "teardown")
+					return nil, nil
+				},
+			},
+		)
+
+		values, err := ps.SetupWithValues("user exists", map[string]interface{}{"id": float64(7)})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"id": float64(7)}, values)
+		require.NoError(t, ps.Teardown("user exists", nil))
+		assert.Equal(t, []string{"setup", "teardown"}, calls)
+	})
+
+	t.Run("falls back to the setup URL for other states", func(t *testing.T) {
+		var received string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			received = body["state"].(string)
+		}))
+		defer server.Close()
+
+		ps := verifier.NewProviderStates(server.URL)
+		ps.SetHandlers(map[string]verifier.StateHandler{
+			"user exists": func(map[string]interface{}) (map[string]interface{}, error) { return nil, nil },
+		}, nil)
+
+		require.NoError(t, ps.Setup("order exists", nil))
+		assert.Equal(t, "order exists", received)
+	})
+
+	t.Run("does not tear down in-process states through the setup URL", func(t *testing.T) {
+		var actions []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			actions = append(actions, body["state"].(string)+" "+body["action"].(string))
+		}))
+		defer server.Close()
+
+		ps := verifier.NewProviderStates(server.URL)
+		ps.SetHandlers(map[string]verifier.StateHandler{
+			"user exists": func(map[string]interface{}) (map[string]interface{}, error) { return nil, nil },
+		}, nil)
+
+		require.NoError(t, ps.Setup("user exists", nil))
+		require.NoError(t, ps.Teardown("user exists", nil))
+		require.NoError(t, ps.Setup("order exists", nil))
+		require.NoError(t, ps.Teardown("order exists", nil))
+		assert.Equal(t, []string{"order exists setup", "order exists teardown"}, actions)
+	})
+
+	t.Run("fails for states without a handler", func(t *testing.T) {
+		ps := verifier.NewProviderStates("")
+		ps.SetHandlers(map[string]verifier.StateHandler{
+			"user exists": func(map[string]interface{}) (map[string]interface{}, error) { return nil, nil },
+		}, nil)
+
+		err := ps.Setup("order exists", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `no handler for provider state "order exists"`)
+		assert.NoError(t, ps.Teardown("order exists", nil))
+	})
+
+	t.Run("reports handler errors", func(t *testing.T) {
+		ps := verifier.NewProviderStates("")
+		ps.SetHandlers(map[string]verifier.StateHandler{
+			"user exists": func(map[string]interface{}) (map[string]interface{}, error) {
+				return nil, errors.New("database unavailable")
+			},
+		}, nil)
+
+		err := ps.Setup("user exists", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "database unavailable")
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/jt-chihara/yakusoku/internal/matcher"
 	"github.com/jt-chihara/yakusoku/internal/verifier"
 )
 
@@ -420,3 +421,44 @@ func TestReporter_FailureDetails(t *testing.T) {
 		assert.Contains(t, output, "Server Error")
 	})
 }
+
+func TestReporter_Mismatches(t *testing.T) {
+	t.Run("lists one mismatch per line", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := verifier.NewReporter(&buf)
+
+		result := verifier.VerificationResult{
+			Success: false,
+			Interactions: []verifier.InteractionResult{
+				{
+					Description: "get user 1",
+					Success:     false,
+					Mismatches: []matcher.Mismatch{
+						{Kind: matcher.KindStatus, Expected: 200, Actual: 404, Message: "expected 200, got 404"},
+						{Kind: matcher.KindBody, Path: "$.id", Expected: "1", Actual: "a", Matcher: "regex", Message: `expected value matching \d+, got a`},
+					},
+				},
+			},
+		}
+
+		reporter.Report(&result)
+		output := buf.String()
+
+		assert.Contains(t, output, "      status: expected 200, got 404\n")
+		assert.Contains(t, output, "      $.id (regex): expected value matching \\d+, got a\n")
+	})
+}
+
+func TestReporter_ReportTotal(t *testing.T) {
+	t.Run("reports totals over several contracts", func(t *testing.T) {
+		var buf bytes.Buffer
+		reporter := verifier.NewReporter(&buf)
+
+		reporter.ReportTotal([]*verifier.VerificationResult{
+			{Success: true, Interactions: []verifier.InteractionResult{{Success: true}, {Success: true}}},
+			{Success: false, Interactions: []verifier.InteractionResult{{Success: true}, {Success: false}}},
+		})
+
+		assert.Contains(t, buf.String(), "Total: 2 contracts (1 failed), 3 interactions passed, 1 failed")
+	})
+}
@@ -1,9 +1,19 @@
 package verifier_test
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -213,3 +223,967 @@ func TestVerifier_ProviderStates(t *testing.T) {
 		assert.Contains(t, statesCalled, "/provider-states")
 	})
 }
+
+func TestVerifier_ProviderStateTeardown(t *testing.T) {
+	interaction := contract.Interaction{
+		Description:    "get user 1",
+		ProviderStates: []contract.ProviderState{{Name: "user 1 exists"}, {Name: "user 1 is active"}},
+		Request:        contract.Request{Method: "GET", Path: "/users/1"},
+		Response:       contract.Response{Status: 200},
+	}
+
+	newStatesServer := func(calls *[]string, failOn string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			call := fmt.Sprintf("%s %s", body["action"], body["state"])
+			*calls = append(*calls, call)
+			if call == failOn {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+	}
+
+	t.Run("tears down states after a failed verification", func(t *testing.T) {
+		var calls []string
+		states := newStatesServer(&calls, "")
+		defer states.Close()
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        provider.URL,
+			ProviderStatesSetupURL: states.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, []string{
+			"setup user 1 exists",
+			"setup user 1 is active",
+			"teardown user 1 is active",
+			"teardown user 1 exists",
+		}, calls)
+	})
+
+	t.Run("tears down states set up before a setup failure", func(t *testing.T) {
+		var calls []string
+		states := newStatesServer(&calls, "setup user 1 is active")
+		defer states.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        "http://127.0.0.1:0",
+			ProviderStatesSetupURL: states.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "failed to setup provider state")
+		assert.Equal(t, []string{
+			"setup user 1 exists",
+			"setup user 1 is active",
+			"teardown user 1 exists",
+		}, calls)
+	})
+
+	t.Run("reports teardown failures", func(t *testing.T) {
+		var calls []string
+		states := newStatesServer(&calls, "teardown user 1 exists")
+		defer states.Close()
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        provider.URL,
+			ProviderStatesSetupURL: states.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "failed to teardown provider states")
+	})
+}
+
+func TestVerifier_ProviderStateGenerators(t *testing.T) {
+	newInteraction := func(expression string) contract.Interaction {
+		stateGen := func(expr string) contract.Generator {
+			return contract.Generator{Type: "ProviderState", Expression: expr}
+		}
+		return contract.Interaction{
+			Description:    "update order",
+			ProviderStates: []contract.ProviderState{{Name: "an order exists", Params: map[string]interface{}{"tenant": "acme"}}},
+			Request: contract.Request{
+				Method:  "PUT",
+				Path:    "/orders/1",
+				Query:   contract.Query{"tenant": {"example"}},
+				Headers: map[string]interface{}{"X-Order-Id": "1"},
+				Body: map[string]interface{}{
+					"id":    float64(1),
+					"items": []interface{}{map[string]interface{}{"orderId": float64(1)}},
+				},
+				Generators: contract.Generators{
+					Path:    stateGen(expression),
+					Query:   map[string]contract.Generator{"tenant": stateGen("${tenant}")},
+					Headers: map[string]contract.Generator{"X-Order-Id": stateGen("${id}")},
+					Body: map[string]contract.Generator{
+						"$.id":               stateGen("${id}"),
+						"$.items[*].orderId": stateGen("${id}"),
+					},
+				},
+			},
+			Response: contract.Response{Status: 200},
+		}
+	}
+
+	states := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	defer states.Close()
+
+	t.Run("substitutes setup values into the request", func(t *testing.T) {
+		var path, tenant, header string
+		var body map[string]interface{}
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			tenant = r.URL.Query().Get("tenant")
+			header = r.Header.Get("X-Order-Id")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}))
+		defer provider.Close()
+
+		c := newContract(newInteraction("/orders/${id}"))
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        provider.URL,
+			ProviderStatesSetupURL: states.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Error)
+
+		assert.Equal(t, "/orders/42", path)
+		assert.Equal(t, "acme", tenant)
+		assert.Equal(t, "42", header)
+		assert.Equal(t, float64(42), body["id"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"orderId": float64(42)}}, body["items"])
+		assert.Equal(t, "/orders/42", result.Interactions[0].RequestPath)
+
+		// The contract is left untouched
+		assert.Equal(t, "/orders/1", c.Interactions[0].Request.Path)
+		assert.Equal(t, float64(1), c.Interactions[0].Request.Body.(map[string]interface{})["id"])
+	})
+
+	t.Run("writes large numeric values in full", func(t *testing.T) {
+		largeStates := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"id": 1234567}`))
+		}))
+		defer largeStates.Close()
+
+		var path, header string
+		var body json.RawMessage
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			header = r.Header.Get("X-Order-Id")
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		}))
+		defer provider.Close()
+
+		c := newContract(newInteraction("/orders/${id}"))
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        provider.URL,
+			ProviderStatesSetupURL: largeStates.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Error)
+
+		assert.Equal(t, "/orders/1234567", path)
+		assert.Equal(t, "1234567", header)
+		assert.JSONEq(t, `{"id": 1234567, "items": [{"orderId": 1234567}]}`, string(body))
+	})
+
+	t.Run("fails when a value is missing", func(t *testing.T) {
+		c := newContract(newInteraction("/orders/${orderId}"))
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL:        "http://127.0.0.1:0",
+			ProviderStatesSetupURL: states.URL,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, `no provider state value for "orderId"`)
+	})
+}
+
+func TestVerifier_NumberMatchers(t *testing.T) {
+	interaction := contract.Interaction{
+		Description: "get payment",
+		Request:     contract.Request{Method: "GET", Path: "/payments/1"},
+		Response: contract.Response{
+			Status: 200,
+			Body:   map[string]interface{}{"amount": float64(100)},
+			MatchingRules: contract.MatchingRules{
+				Body: map[string]contract.MatcherSet{
+					"$.amount": {Matchers: []contract.Matcher{{Match: "integer"}}},
+				},
+			},
+		},
+	}
+
+	t.Run("integer rule passes integer token", func(t *testing.T) {
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"amount":2500}`))
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Diff)
+	})
+
+	t.Run("integer rule rejects decimal token with whole value", func(t *testing.T) {
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"amount":2500.0}`))
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Diff, "expected an integer")
+		require.Len(t, result.Interactions[0].Mismatches, 1)
+		assert.Equal(t, "$.amount", result.Interactions[0].Mismatches[0].Path)
+		assert.Equal(t, "integer", result.Interactions[0].Mismatches[0].Matcher)
+	})
+}
+
+func TestVerifier_NonJSONBodies(t *testing.T) {
+	verify := func(t *testing.T, handler http.HandlerFunc, response contract.Response) verifier.InteractionResult {
+		t.Helper()
+		provider := httptest.NewServer(handler)
+		defer provider.Close()
+
+		c := contract.Contract{
+			Consumer: contract.Pacticipant{Name: "Consumer"},
+			Provider: contract.Pacticipant{Name: "Provider"},
+			Interactions: []contract.Interaction{
+				{
+					Description: "get document",
+					Request:     contract.Request{Method: "GET", Path: "/document"},
+					Response:    response,
+				},
+			},
+		}
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+		require.NoError(t, err)
+		return result.Interactions[0]
+	}
+
+	t.Run("compares plain text bodies as strings", func(t *testing.T) {
+		ir := verify(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Write([]byte("id,name\n1,Jane\n"))
+		}, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "text/csv"},
+			Body:    "id,name\n1,Jane\n",
+		})
+		assert.True(t, ir.Success, ir.Diff+ir.Error)
+		assert.Equal(t, "id,name\n1,Jane\n", ir.ActualBodyRaw)
+	})
+
+	t.Run("reports text body mismatches", func(t *testing.T) {
+		ir := verify(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<p>Goodbye</p>"))
+		}, contract.Response{
+			Status: 200,
+			Body:   "<p>Hello</p>",
+		})
+		assert.False(t, ir.Success)
+		assert.Empty(t, ir.Error)
+		assert.Contains(t, ir.Diff, "$: expected <p>Hello</p> (string), got <p>Goodbye</p> (string)")
+	})
+
+	t.Run("matches binary bodies by content type", func(t *testing.T) {
+		ir := verify(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"))
+		}, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "application/pdf"},
+			Body:    "JVBERi0xLjQ=",
+			MatchingRules: contract.MatchingRules{
+				Body: map[string]contract.MatcherSet{
+					"$": {Matchers: []contract.Matcher{{Match: "contentType", Value: "application/pdf"}}},
+				},
+			},
+		})
+		assert.True(t, ir.Success, ir.Diff+ir.Error)
+	})
+
+	t.Run("compares binary bodies with the decoded contract body", func(t *testing.T) {
+		pdf := []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(pdf)
+		}
+		encoded := base64.StdEncoding.EncodeToString(pdf)
+
+		ir := verify(t, handler, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "application/pdf"},
+			Body:    encoded,
+		})
+		assert.True(t, ir.Success, ir.Diff+ir.Error)
+
+		ir = verify(t, handler, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "application/pdf"},
+			Body:    map[string]interface{}{"content": encoded, "contentType": "application/pdf", "encoded": "base64"},
+		})
+		assert.True(t, ir.Success, ir.Diff+ir.Error)
+
+		ir = verify(t, handler, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "application/pdf"},
+			Body:    base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n")),
+		})
+		assert.False(t, ir.Success)
+	})
+
+	t.Run("still fails on malformed JSON", func(t *testing.T) {
+		ir := verify(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{broken"))
+		}, contract.Response{
+			Status:  200,
+			Headers: map[string]interface{}{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"id": float64(1)},
+		})
+		assert.False(t, ir.Success)
+		assert.Contains(t, ir.Error, "failed to parse response body")
+	})
+}
+
+func TestVerifier_Query(t *testing.T) {
+	var received url.Values
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		if r.URL.Query().Get("status") != "active" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer provider.Close()
+
+	c := contract.Contract{
+		Consumer: contract.Pacticipant{Name: "Consumer"},
+		Provider: contract.Pacticipant{Name: "Provider"},
+		Interactions: []contract.Interaction{
+			{
+				Description: "search users",
+				Request: contract.Request{
+					Method: "GET",
+					Path:   "/users",
+					Query:  contract.Query{"status": {"active"}, "id": {"1", "2"}},
+				},
+				Response: contract.Response{Status: 200},
+			},
+		},
+	}
+
+	result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Interactions[0].Diff)
+	assert.Equal(t, []string{"1", "2"}, received["id"])
+}
+
+func TestVerifier_RequestBodyEncoding(t *testing.T) {
+	type received struct {
+		contentType string
+		body        []byte
+		form        url.Values
+	}
+
+	verify := func(t *testing.T, request contract.Request) received {
+		t.Helper()
+		var got received
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got.contentType = r.Header.Get("Content-Type")
+			if strings.HasPrefix(got.contentType, "multipart/form-data") {
+				require.NoError(t, r.ParseMultipartForm(1<<20))
+				got.form = r.MultipartForm.Value
+			} else {
+				got.body, _ = io.ReadAll(r.Body)
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer provider.Close()
+
+		c := contract.Contract{
+			Consumer: contract.Pacticipant{Name: "Consumer"},
+			Provider: contract.Pacticipant{Name: "Provider"},
+			Interactions: []contract.Interaction{
+				{Description: "send body", Request: request, Response: contract.Response{Status: 200}},
+			},
+		}
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+		require.NoError(t, err)
+		require.True(t, result.Success, result.Interactions[0].Error)
+		return got
+	}
+
+	t.Run("encodes JSON by default", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method: "POST",
+			Path:   "/users",
+			Body:   map[string]interface{}{"name": "Jane"},
+		})
+		assert.JSONEq(t, `{"name":"Jane"}`, string(got.body))
+	})
+
+	t.Run("encodes form bodies", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "POST",
+			Path:    "/login",
+			Headers: map[string]interface{}{"content-type": "application/x-www-form-urlencoded"},
+			Body:    map[string]interface{}{"user": "jane doe", "scope": []interface{}{"read", "write"}},
+		})
+		form, err := url.ParseQuery(string(got.body))
+		require.NoError(t, err)
+		assert.Equal(t, "jane doe", form.Get("user"))
+		assert.Equal(t, []string{"read", "write"}, form["scope"])
+	})
+
+	t.Run("writes form numbers in full", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "POST",
+			Path:    "/payments",
+			Headers: map[string]interface{}{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:    map[string]interface{}{"amount": float64(1500000), "rate": 0.25},
+		})
+		form, err := url.ParseQuery(string(got.body))
+		require.NoError(t, err)
+		assert.Equal(t, "1500000", form.Get("amount"))
+		assert.Equal(t, "0.25", form.Get("rate"))
+	})
+
+	t.Run("sends raw form strings as is", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "POST",
+			Path:    "/login",
+			Headers: map[string]interface{}{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:    "user=jane&password=secret",
+		})
+		assert.Equal(t, "user=jane&password=secret", string(got.body))
+	})
+
+	t.Run("sends text bodies unquoted", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "POST",
+			Path:    "/notes",
+			Headers: map[string]interface{}{"Content-Type": "text/plain; charset=utf-8"},
+			Body:    "hello",
+		})
+		assert.Equal(t, "hello", string(got.body))
+	})
+
+	t.Run("encodes multipart bodies with a boundary", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "POST",
+			Path:    "/upload",
+			Headers: map[string]interface{}{"Content-Type": "multipart/form-data"},
+			Body:    map[string]interface{}{"title": "report"},
+		})
+		assert.Contains(t, got.contentType, "boundary=")
+		assert.Equal(t, []string{"report"}, got.form["title"])
+	})
+
+	t.Run("decodes base64 binary bodies", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "PUT",
+			Path:    "/avatar",
+			Headers: map[string]interface{}{"Content-Type": "image/png"},
+			Body:    "iVBORw0KGgo=",
+		})
+		assert.Equal(t, []byte("\x89PNG\r\n\x1a\n"), got.body)
+	})
+
+	t.Run("decodes v4 encoded bodies", func(t *testing.T) {
+		got := verify(t, contract.Request{
+			Method:  "PUT",
+			Path:    "/avatar",
+			Headers: map[string]interface{}{"Content-Type": "application/octet-stream"},
+			Body:    map[string]interface{}{"content": "AAEC", "contentType": "application/octet-stream", "encoded": "base64"},
+		})
+		assert.Equal(t, []byte{0, 1, 2}, got.body)
+	})
+}
+
+func TestVerifier_ResponseHeaders(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Header().Add("Cache-Control", "no-cache")
+		w.Header().Add("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer provider.Close()
+
+	c := contract.Contract{
+		Consumer: contract.Pacticipant{Name: "Consumer"},
+		Provider: contract.Pacticipant{Name: "Provider"},
+		Interactions: []contract.Interaction{
+			{
+				Description: "get page",
+				Request:     contract.Request{Method: "GET", Path: "/page"},
+				Response: contract.Response{
+					Status: 200,
+					Headers: map[string]interface{}{
+						"content-type":  "text/html;charset=utf-8",
+						"cache-control": "no-cache, no-store",
+					},
+				},
+			},
+		},
+	}
+
+	result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Interactions[0].Diff)
+	assert.Equal(t, "no-cache, no-store", result.Interactions[0].ActualHeaders["Cache-Control"])
+}
+
+func TestVerifier_ListHeaderValues(t *testing.T) {
+	var accept string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Add("Cache-Control", "no-cache")
+		w.Header().Add("Cache-Control", "no-store")
+	}))
+	defer provider.Close()
+
+	c := contract.Contract{
+		Consumer: contract.Pacticipant{Name: "Consumer"},
+		Provider: contract.Pacticipant{Name: "Provider"},
+		Interactions: []contract.Interaction{
+			{
+				Description: "get page",
+				Request: contract.Request{
+					Method:  "GET",
+					Path:    "/page",
+					Headers: map[string]interface{}{"Accept": []interface{}{"application/json", "text/plain"}},
+				},
+				Response: contract.Response{
+					Status:  200,
+					Headers: map[string]interface{}{"Cache-Control": []interface{}{"no-cache", "no-store"}},
+				},
+			},
+		},
+	}
+
+	result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL}).Verify(&c)
+	require.NoError(t, err)
+	assert.True(t, result.Success, result.Interactions[0].Diff)
+	assert.Equal(t, "application/json, text/plain", accept)
+}
+
+func TestVerifier_Handler(t *testing.T) {
+	interaction := contract.Interaction{
+		Description: "create user",
+		Request: contract.Request{
+			Method:  "POST",
+			Path:    "/users",
+			Query:   contract.Query{"notify": {"true"}},
+			Headers: map[string]interface{}{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": "Jane"},
+		},
+		Response: contract.Response{
+			Status:  201,
+			Headers: map[string]interface{}{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": "Jane", "notified": true},
+		},
+	}
+
+	t.Run("verifies against a handler in memory", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+			var user map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&user))
+			user["notified"] = r.URL.Query().Get("notify") == "true"
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(user)
+		})
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{Handler: mux}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Diff+result.Interactions[0].Error)
+	})
+
+	t.Run("reports handler panics as errors", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{Handler: handler}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "provider handler panicked: boom")
+	})
+}
+
+func TestVerifier_Parallel(t *testing.T) {
+	interaction := func(description, state string) contract.Interaction {
+		i := contract.Interaction{
+			Description: description,
+			Request:     contract.Request{Method: "GET", Path: "/" + description},
+			Response:    contract.Response{Status: 200},
+		}
+		if state != "" {
+			i.ProviderStates = []contract.ProviderState{{Name: state}}
+		}
+		return i
+	}
+
+	// tracker counts the requests in flight, overall and per state (the
+	// part of the path before "-").
+	type tracker struct {
+		mu       sync.Mutex
+		inFlight map[string]int
+		maxTotal int
+		maxState map[string]int
+		total    int
+	}
+	newProvider := func(tr *tracker) *httptest.Server {
+		tr.inFlight = map[string]int{}
+		tr.maxState = map[string]int{}
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			state := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "-", 2)[0]
+			tr.mu.Lock()
+			tr.total++
+			tr.inFlight[state]++
+			tr.maxTotal = max(tr.maxTotal, tr.total)
+			tr.maxState[state] = max(tr.maxState[state], tr.inFlight[state])
+			tr.mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			tr.mu.Lock()
+			tr.total--
+			tr.inFlight[state]--
+			tr.mu.Unlock()
+		}))
+	}
+
+	t.Run("verifies independent interactions concurrently in stable order", func(t *testing.T) {
+		var tr tracker
+		provider := newProvider(&tr)
+		defer provider.Close()
+
+		c := contract.Contract{Interactions: []contract.Interaction{
+			interaction("a-1", ""), interaction("b-1", ""), interaction("c-1", ""), interaction("d-1", ""),
+		}}
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL, Parallel: 4}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Greater(t, tr.maxTotal, 1)
+
+		var descriptions []string
+		for _, ir := range result.Interactions {
+			descriptions = append(descriptions, ir.Description)
+		}
+		assert.Equal(t, []string{"a-1", "b-1", "c-1", "d-1"}, descriptions)
+	})
+
+	t.Run("serializes interactions sharing a provider state", func(t *testing.T) {
+		var tr tracker
+		provider := newProvider(&tr)
+		defer provider.Close()
+
+		c := contract.Contract{Interactions: []contract.Interaction{
+			interaction("a-1", "a"), interaction("b-1", "b"), interaction("a-2", "a"),
+			interaction("b-2", "b"), interaction("a-3", "a"), interaction("b-3", "b"),
+		}}
+		result, err := verifier.New(verifier.Config{ProviderBaseURL: provider.URL, Parallel: 4}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, 1, tr.maxState["a"])
+		assert.Equal(t, 1, tr.maxState["b"])
+		assert.Equal(t, "a-2", result.Interactions[2].Description)
+	})
+}
+
+func TestVerifier_Resilience(t *testing.T) {
+	interaction := contract.Interaction{
+		Description: "create user",
+		Request: contract.Request{
+			Method:  "POST",
+			Path:    "/users",
+			Headers: map[string]interface{}{"Content-Type": "application/json"},
+			Body:    map[string]interface{}{"name": "Jane"},
+		},
+		Response: contract.Response{Status: 201},
+	}
+
+	t.Run("fails requests that exceed the timeout", func(t *testing.T) {
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(300 * time.Millisecond)
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			RequestTimeout:  50 * time.Millisecond,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "Timeout")
+	})
+
+	t.Run("retries requests whose connection is refused", func(t *testing.T) {
+		addr := closedAddr(t)
+
+		var mu sync.Mutex
+		var bodies []string
+		server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			bodies = append(bodies, string(body))
+			mu.Unlock()
+			w.WriteHeader(http.StatusCreated)
+		})}
+		defer server.Close()
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return
+			}
+			server.Serve(listener)
+		}()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: "http://" + addr,
+			Retries:         5,
+			RetryBackoff:    50 * time.Millisecond,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Error)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{`{"name":"Jane"}`}, bodies)
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: "http://" + closedAddr(t),
+			Retries:         1,
+			RetryBackoff:    time.Millisecond,
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "connection error")
+		assert.Contains(t, result.Interactions[0].Error, "refused")
+	})
+
+	t.Run("does not retry timeouts", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+		}))
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			RequestTimeout:  50 * time.Millisecond,
+			Retries:         2,
+			RetryBackoff:    time.Millisecond,
+		}).Verify(&c)
+		close(release)
+		provider.Close()
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("does not retry errors other than failed connections", func(t *testing.T) {
+		calls := 0
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			panic("handler failed")
+		})
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{Handler: handler, Retries: 2}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("waits for the provider to become ready", func(t *testing.T) {
+		var mu sync.Mutex
+		var requests []string
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests = append(requests, r.URL.Path)
+			if r.URL.Path == "/health" {
+				if len(requests) < 3 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			WaitURL:         provider.URL + "/health",
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, []string{"/health", "/health", "/health", "/users"}, requests)
+	})
+
+	t.Run("returns an error when the provider never becomes ready", func(t *testing.T) {
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer provider.Close()
+
+		c := newContract(interaction)
+		_, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			WaitURL:         provider.URL + "/health",
+			WaitTimeout:     300 * time.Millisecond,
+		}).Verify(&c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider not ready")
+		assert.Contains(t, err.Error(), "status 503")
+	})
+
+	t.Run("bounds each readiness poll by the wait timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer provider.Close()
+		defer close(release)
+
+		c := newContract(interaction)
+		start := time.Now()
+		_, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			WaitURL:         provider.URL + "/health",
+			WaitTimeout:     300 * time.Millisecond,
+		}).Verify(&c)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "provider not ready")
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+}
+
+// newContract returns a contract between Consumer and Provider with the
+// given interactions.
+func newContract(interactions ...contract.Interaction) contract.Contract {
+	return contract.Contract{
+		Consumer:     contract.Pacticipant{Name: "Consumer"},
+		Provider:     contract.Pacticipant{Name: "Provider"},
+		Interactions: interactions,
+	}
+}
+
+// closedAddr returns a local address that refuses connections.
+func closedAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestVerifier_RequestFilter(t *testing.T) {
+	interaction := contract.Interaction{
+		Description: "create user",
+		Request: contract.Request{
+			Method: "POST",
+			Path:   "/users",
+			Headers: map[string]interface{}{
+				"Authorization": "Bearer placeholder",
+				"Content-Type":  "application/json",
+			},
+			Body: map[string]interface{}{"name": "Jane"},
+		},
+		Response: contract.Response{Status: 201},
+	}
+	var received *http.Request
+	var receivedBody string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received, receivedBody = r, string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer provider.Close()
+
+	t.Run("replaces contract headers with custom headers", func(t *testing.T) {
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			CustomHeaders:   map[string]string{"Authorization": "Bearer secret", "X-Tenant": "acme"},
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Error)
+		assert.Equal(t, "Bearer secret", received.Header.Get("Authorization"))
+		assert.Equal(t, "acme", received.Header.Get("X-Tenant"))
+		assert.Equal(t, "Bearer placeholder", result.Interactions[0].RequestHeaders["Authorization"])
+	})
+
+	t.Run("applies the filter after custom headers", func(t *testing.T) {
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			CustomHeaders:   map[string]string{"X-Tenant": "acme"},
+			RequestFilter: func(req *http.Request) error {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return err
+				}
+				req.Header.Set("X-Signature", req.Header.Get("X-Tenant")+":"+string(body))
+				req.Body = io.NopCloser(strings.NewReader(`{"name":"John"}`))
+				return nil
+			},
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.True(t, result.Success, result.Interactions[0].Error)
+		assert.Equal(t, `acme:{"name":"Jane"}`, received.Header.Get("X-Signature"))
+		assert.Equal(t, `{"name":"John"}`, receivedBody)
+	})
+
+	t.Run("reports filter errors", func(t *testing.T) {
+		c := newContract(interaction)
+		result, err := verifier.New(verifier.Config{
+			ProviderBaseURL: provider.URL,
+			RequestFilter: func(req *http.Request) error {
+				return fmt.Errorf("token expired")
+			},
+		}).Verify(&c)
+		require.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Interactions[0].Error, "request filter failed: token expired")
+	})
+}